@@ -59,6 +59,14 @@ func main() {
 		cmd2 = exec.Command(path2, "test", "true")
 	}
 
+	// Optional players for each file, e.g. "testHarness engine mcts" plays minimax against monte carlo tree search
+	if len(os.Args) > 1 && os.Args[1] != "" {
+		cmd1.Args = append(cmd1.Args, os.Args[1])
+	}
+	if len(os.Args) > 2 && os.Args[2] != "" {
+		cmd2.Args = append(cmd2.Args, os.Args[2])
+	}
+
 
 	stdIn1, err := cmd1.StdinPipe()
 	detectError(err, "error creating stdIn1")
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"github.com/ktodaz/gobot/gobotcore"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// gobot analyze [-variant standard|mini|large] [-position pos | -game record] [-depth 8] [-movetime 5s] [-multipv 3] [-searchmoves "C3C4 D3D4"] [-evaluator classic|weights]
+// [-trace file.dot|file.json] [-trace-ply 3] [-trace-nodes 5000]
+// Prints the best lines after every completed depth, and the final ones with their scores.
+// -trace writes the tree of the last iteration for Graphviz or as JSON, see gobotcore.Trace
+func analyze(args []string) {
+	flags := flag.NewFlagSet("analyze", flag.ExitOnError)
+	variantName := variantFlag(flags)
+	position := flags.String("position", "", "position to analyze, the start position of the variant if empty")
+	record := flags.String("game", "", "game record to analyze the final position of")
+	depth := flags.Int("depth", 0, "depth to search to, 0 for no limit")
+	moveTime := flags.Duration("movetime", 5*time.Second, "how long to search, 0 for no limit")
+	multiPV := flags.Int("multipv", 3, "how many of the best moves to show")
+	searchMoves := flags.String("searchmoves", "", "moves separated by spaces to search instead of every legal move")
+	evaluator := flags.String("evaluator", "classic", "how to score positions: classic or a network weights file from gobot train")
+	tracePath := flags.String("trace", "", "file to write the search tree to, the extension picks the format: .dot or .json")
+	tracePly := flags.Int("trace-ply", 3, "plies from the root to trace, 0 for all")
+	traceNodes := flags.Int("trace-nodes", 5000, "most nodes to trace, 0 for no limit")
+	flags.Parse(args)
+	useVariant(*variantName)
+	if *position == "" {
+		*position = gobotcore.CurrentVariant().Start
+	}
+
+	// 0 leaves the depth to -movetime. Anything past the int8 depth would wrap around
+	if *depth < 0 || *depth > int(gobotcore.MaxSearchDepth) {
+		analyzeFail(fmt.Sprintf("-depth must be from 1 to %d, or 0 for no limit", gobotcore.MaxSearchDepth))
+	}
+	limits := gobotcore.SearchLimits{Depth: int8(*depth), MoveTime: *moveTime}
+	if limits.Depth <= 0 && limits.MoveTime <= 0 {
+		analyzeFail("-depth or -movetime has to limit the search")
+	}
+	for _, str := range strings.Fields(*searchMoves) {
+		move, err := gobotcore.ParseMove(str)
+		if err != nil {
+			analyzeFail(err.Error())
+		}
+		limits.SearchMoves = append(limits.SearchMoves, move)
+	}
+
+	var game *gobotcore.Game
+	var err error
+	if *record != "" {
+		game, err = gobotcore.LoadGameRecord(*record)
+	} else {
+		game, err = gobotcore.NewGameFromPosition(*position)
+	}
+	if err != nil {
+		analyzeFail(err.Error())
+	}
+
+	gobotcore.SetDebug(false)
+	board := game.Board()
+	player := game.Turn()
+	renderer.Print(&board)
+	fmt.Println(player.Name() + " to move")
+	options := gobotcore.SearchOptions{MultiPV: *multiPV}
+	if options.Network, err = gobotcore.ParseEvaluator(*evaluator); err != nil {
+		analyzeFail(err.Error())
+	}
+	if *tracePath != "" {
+		ext := strings.ToLower(filepath.Ext(*tracePath))
+		if ext != ".dot" && ext != ".json" {
+			analyzeFail(fmt.Sprintf("don't know how to write %s, use .dot or .json", *tracePath))
+		}
+		options.Trace = gobotcore.NewTrace(*tracePly, *traceNodes)
+	}
+	lines := board.SearchLines(&player, limits, options, nil, func(info gobotcore.SearchInfo) {
+		fmt.Printf("depth %2d  %-10s  %s  (%d nodes, %v)\n", info.Depth, gobotcore.FormatScore(info.Score), info.PV.ToString(), info.Nodes, info.Time.Round(time.Millisecond))
+	})
+
+	if options.Trace != nil {
+		writeTrace(options.Trace, *tracePath)
+	}
+	if len(lines) == 0 {
+		fmt.Println(player.Name() + " has no legal move")
+		return
+	}
+	fmt.Println()
+	for i, line := range lines {
+		fmt.Printf("%d. %s  %-10s  %s\n", i+1, line.Move().ToString(), gobotcore.FormatScore(*line.Score()), line.PV().ToString())
+	}
+}
+
+func writeTrace(trace *gobotcore.Trace, path string) {
+	file, err := os.Create(path)
+	if err != nil {
+		analyzeFail(err.Error())
+	}
+	if strings.ToLower(filepath.Ext(path)) == ".json" {
+		err = trace.WriteJSON(file)
+	} else {
+		err = trace.WriteDOT(file)
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(path)
+		analyzeFail(err.Error())
+	}
+	if trace.Truncated() {
+		fmt.Println("The trace was cut short by -trace-nodes")
+	}
+	fmt.Println("Wrote the search tree to " + path)
+}
+
+func analyzeFail(message string) {
+	fmt.Fprintln(os.Stderr, "gobot analyze:", message)
+	os.Exit(2)
+}
@@ -0,0 +1,167 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"github.com/ktodaz/gobot/gobotcore"
+	"os"
+	"strings"
+	"time"
+)
+
+// How long Gobot thinks about a hint
+const hintTime = 2 * time.Second
+
+var stdin = bufio.NewReader(os.Stdin)
+
+const commandHelp = `Enter a move like C3C4, or one of these commands:
+  undo         take back your last move and the reply to it
+  hint         ask Gobot for the best move
+  moves        list your legal moves
+  board        show the board again
+  save <file>  save the game record to a file
+  load <file>  continue a game from a saved record
+  resign       give up the game
+  quit         leave without finishing the game
+`
+
+// Reads lines at the "Enter a move:" prompt until the human makes a move.
+// Returns false if the human resigned or quit
+func humanMoveFriendly() bool {
+	for {
+		if isHumanVsHuman() {
+			fmt.Print(game.Turn().Name() + ", enter a move: ")
+		} else {
+			fmt.Print("Enter a move: ")
+		}
+		line, err := stdin.ReadString('\n')
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			if err != nil {
+				fmt.Println()
+				return false
+			}
+			continue
+		}
+
+		switch strings.ToLower(fields[0]) {
+		case "help", "?":
+			fmt.Print(commandHelp)
+		case "undo":
+			if undoFriendly() {
+				autosave()
+				renderer.LastMove = nil
+				renderer.Print(&board)
+			}
+		case "hint":
+			hintFriendly()
+		case "moves":
+			moves := game.LegalMoves()
+			fmt.Println("Legal moves: " + moves.ToString())
+		case "board":
+			renderer.Print(&board)
+		case "save":
+			if len(fields) != 2 {
+				fmt.Println("Usage: save <file>")
+			} else if err := game.SaveRecord(fields[1]); err != nil {
+				fmt.Println("Could not save the game: " + err.Error())
+			} else {
+				fmt.Println("Saved the game to " + fields[1])
+			}
+		case "load":
+			if len(fields) != 2 {
+				fmt.Println("Usage: load <file>")
+			} else if loadFriendly(fields[1]) {
+				// The other side may be the one to move in the loaded game
+				return true
+			}
+		case "resign":
+			side := game.Turn()
+			fmt.Printf("%s resigned. %s Won\n", side.Name(), side.Opponent().Name())
+			return false
+		case "quit", "exit":
+			return false
+		default:
+			if playHumanMove(fields[0]) {
+				return true
+			}
+		}
+	}
+}
+
+// Plays the move if it is legal, or explains why it isn't
+func playHumanMove(input string) bool {
+	move, err := gobotcore.ParseMove(input)
+	if err != nil {
+		fmt.Println("Enter a move like C3C4, or help for commands")
+		return false
+	}
+	if reason := board.WhyIllegal(&move, game.Turn()); reason != "" {
+		fmt.Println(reason)
+		return false
+	}
+	game.MakeMove(move)
+	board = game.Board()
+	return true
+}
+
+// Takes back moves until the last move of the side to move is gone, so it is their turn again
+func undoFriendly() bool {
+	side := game.Turn()
+	hasMove := false
+	for _, played := range game.History() {
+		if played.Player == side {
+			hasMove = true
+		}
+	}
+	if !hasMove {
+		fmt.Println("There is no move of yours to take back.")
+		return false
+	}
+
+	for {
+		played, _ := game.Undo()
+		if played.Player == side {
+			break
+		}
+	}
+	board = game.Board()
+	return true
+}
+
+func hintFriendly() {
+	side := game.Turn()
+	fmt.Println("Thinking...")
+	move := board.Search(&side, gobotcore.SearchLimits{MoveTime: hintTime}, nil, nil)
+	pv := move.PV()
+	if len(pv) == 0 {
+		fmt.Println("Gobot has no hint for this position.")
+		return
+	}
+	fmt.Printf("Hint: %s (score %s)\n", move.Move().ToString(), gobotcore.FormatScore(*move.Score()))
+	if len(pv) > 1 {
+		fmt.Println("Expected line: " + pv.ToString())
+	}
+}
+
+func loadFriendly(path string) bool {
+	loaded, err := gobotcore.LoadGameRecord(path)
+	if err != nil {
+		fmt.Println("Could not load the game: " + err.Error())
+		return false
+	}
+	setGame(loaded)
+	fmt.Println("Loaded the game from " + path)
+	renderer.Print(&board)
+	return true
+}
+
+// Switches to another game, highlighting its last move
+func setGame(newGame *gobotcore.Game) {
+	game = newGame
+	board = game.Board()
+	renderer.LastMove = nil
+	if history := game.History(); len(history) > 0 {
+		renderer.LastMove = &history[len(history)-1].Move
+	}
+}
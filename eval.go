@@ -0,0 +1,41 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"github.com/ktodaz/gobot/gobotcore"
+	"os"
+)
+
+// gobot eval [-variant standard|mini|large] [-position pos] [-personality balanced|aggressive|defensive]
+// Prints every term of the evaluation for each player, and the total from both points of view, see gobotcore.Explain
+func eval(args []string) {
+	flags := flag.NewFlagSet("eval", flag.ExitOnError)
+	variantName := variantFlag(flags)
+	position := flags.String("position", "", "position to evaluate, the start position of the variant if empty")
+	personalityName := flags.String("personality", "balanced", "style whose bonuses to add: balanced, aggressive or defensive")
+	flags.Parse(args)
+	useVariant(*variantName)
+	if *position == "" {
+		*position = gobotcore.CurrentVariant().Start
+	}
+
+	personality, err := gobotcore.ParsePersonality(*personalityName)
+	if err != nil {
+		evalFail(err.Error())
+	}
+	board, player, err := gobotcore.ParsePosition(*position)
+	if err != nil {
+		evalFail(err.Error())
+	}
+
+	renderer.Print(&board)
+	fmt.Println(player.Name() + " to move")
+	fmt.Println()
+	board.Explain(personality).Write(os.Stdout)
+}
+
+func evalFail(message string) {
+	fmt.Fprintln(os.Stderr, "gobot eval:", message)
+	os.Exit(2)
+}
@@ -8,35 +8,84 @@ package main
 import (
 	"fmt"
 	"github.com/ktodaz/gobot/gobotcore"
+	"github.com/ktodaz/gobot/gobotproto"
 	"os"
 	"runtime"
+	"time"
 )
 
 var (
 	board             gobotcore.Board
 	depth             int8 = 7
 	isGobotGoingFirst bool = true
+	renderer          gobotcore.Renderer
+	game              *gobotcore.Game // The interactive game, board is kept in sync with it
 )
 
-// Default: no args
-// Testing: Arg[1] = "test", Arg[2] = "nameOfFile", Arg[3] = "true"/"false"
+// Default: no args, or Arg[1] = "play" followed by the flags in play.go
+// Testing: Arg[1] = "test", Arg[2] = "true"/"false", optional Arg[3] = player from play.go's -gobot flag, e.g. "mcts",
+// optional Arg[4] = "ponder" to let an engine player think while the other program does
+// Text protocol for GUIs and harnesses: Arg[1] = "morph", see package gobotproto
+// JSON API: Arg[1] = "serve", see package gobotserver
+// Full screen terminal UI: Arg[1] = "tui", see package gobottui
+// Board pictures: Arg[1] = "render", see render.go
+// Studying a position: Arg[1] = "analyze", see analyze.go
+// Evaluation breakdown of a position: Arg[1] = "eval", see eval.go
+// Training a network evaluator on self-play games: Arg[1] = "train", see train.go
 func main() {
 	runtime.GOMAXPROCS(runtime.NumCPU())
 	board = gobotcore.NewDefaultBoard()
 
 	if len(os.Args) == 1 {
-		gobotcore.SetDebug(false)
-		isGobotGoingFirst = IsGobotGoingFirst()
-		GameLoop(isGobotGoingFirst)
+		if err := play(nil); err != nil {
+			playFail(err)
+		}
+	} else if os.Args[1] == "play" {
+		if err := play(os.Args[2:]); err != nil {
+			playFail(err)
+		}
 	} else if os.Args[1] == "test" {
 		if os.Args[2] == "false" {
 			isGobotGoingFirst = false
 		}
+		if len(os.Args) > 3 {
+			ponder := len(os.Args) > 4 && os.Args[4] == "ponder"
+			agent, err := newPlayer(playerConfig{kind: os.Args[3], moveTime: 5 * time.Second, ponder: ponder})
+			if err != nil || agent == nil {
+				fmt.Fprintln(os.Stderr, "test needs a player that isn't at the console")
+				os.Exit(2)
+			}
+			players[gobotcore.GOBOT] = agent
+			defer closePlayers()
+		}
 		gobotcore.SetDebug(false)
 		testGameLoop()
+	} else if os.Args[1] == "morph" {
+		gobotcore.SetDebug(false)
+		if err := gobotproto.NewEngine(os.Stdout).Run(os.Stdin); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+	} else if os.Args[1] == "serve" {
+		serve(os.Args[2:])
+	} else if os.Args[1] == "tui" {
+		tui(os.Args[2:])
+	} else if os.Args[1] == "render" {
+		render(os.Args[2:])
+	} else if os.Args[1] == "analyze" {
+		analyze(os.Args[2:])
+	} else if os.Args[1] == "eval" {
+		eval(os.Args[2:])
+	} else if os.Args[1] == "train" {
+		train(os.Args[2:])
 	}
 }
 func testGameLoop() {
+	firstPlayer := gobotcore.Player(gobotcore.HUMAN)
+	if isGobotGoingFirst {
+		firstPlayer = gobotcore.GOBOT
+	}
+	game = gobotcore.NewGameFromBoard(board, firstPlayer)
 	if isGobotGoingFirst {
 		gobotMoveSimple()
 	}
@@ -54,15 +103,19 @@ func humanMoveSimple() {
 	fmt.Scanf("%s", &input)
 	//fmt.Println("Input Received")
 	move := gobotcore.NewMove(gobotcore.NewLocationsFromString(input))
-	board.MakeMoveAndGetTakenPiece(&move)
+	game.MakeMove(move)
+	board = game.Board()
 
 }
 
 func gobotMoveSimple() {
-	gobot := gobotcore.Player(gobotcore.GOBOT)
-	move := board.MinimaxMulti(&gobot, &depth)
-	board.MakeMoveAndGetTakenPiece(move.Move())
-	fmt.Println(move.Move().ToStringFlipped())
+	move, err := players[gobotcore.GOBOT].ChooseMove(game)
+	if err != nil {
+		return
+	}
+	game.MakeMove(move)
+	board = game.Board()
+	fmt.Println(move.ToStringFlipped())
 }
 
 func isGameOver() bool {
@@ -95,36 +148,35 @@ func IsGobotGoingFirst() bool {
 }
 
 func GameLoop(gobotGoingFirst bool) {
-	fmt.Print("\nInitial Board Position:")
-	board.PrintBoard()
-
+	firstPlayer := gobotcore.Player(gobotcore.HUMAN)
 	if gobotGoingFirst {
-		gobotMoveFriendly()
+		firstPlayer = gobotcore.GOBOT
 	}
-	for {
-		humanMoveFriendly()
-		gobotMoveFriendly()
-		if isGameOverFriendly() {
-			break
-		}
-	}
-}
-
-func humanMoveFriendly() {
-	move := getHumanInput()
-	board.MakeMoveAndGetTakenPiece(move)
+	game = gobotcore.NewGameFromBoard(board, firstPlayer)
+	fmt.Print("\nInitial Board Position:")
+	renderer.Print(&board)
+	playGame()
 }
 
-func getHumanInput() *gobotcore.Move {
-	var input string
-
-	for !IsValidInput(input) {
-		fmt.Print("Enter a move: ")
-		fmt.Scan(&input)
+// Plays until the game is over or someone quits. Whoever's turn it is moves next, so loaded games work too
+func playGame() {
+	if players[gobotcore.GOBOT] == nil || players[gobotcore.HUMAN] == nil {
+		fmt.Println("Type help at the prompt for commands.")
+	}
+	for !isGameOverFriendly() {
+		if agent := players[game.Turn()]; agent != nil {
+			if !playerMoveFriendly(agent) {
+				return
+			}
+		} else if !humanMoveFriendly() {
+			return
+		} else if history := game.History(); isHumanVsHuman() && len(history) > 0 {
+			// Show the next person what just happened
+			renderer.LastMove = &history[len(history)-1].Move
+			renderer.Print(&board)
+		}
+		autosave()
 	}
-	loc1, loc2 := gobotcore.NewLocationsFromString(input)
-	move := gobotcore.NewMove(loc1, loc2)
-	return &move
 }
 
 func IsValidInput(input string) bool {
@@ -138,14 +190,6 @@ func IsValidInput(input string) bool {
 	return isOnBoard && board.IsValidHumanMove(&move)
 }
 
-func gobotMoveFriendly() {
-	gobot := gobotcore.Player(gobotcore.GOBOT)
-	move := board.MinimaxMulti(&gobot, &depth)
-	fmt.Printf("\nReturned score: %f", *move.Score())
-	board.MakeMoveAndPrintMessage(move.Move())
-	board.PrintBoard()
-}
-
 func isGameOverFriendly() bool {
 	gobot := gobotcore.Player(gobotcore.GOBOT)
 	gobotMoves := board.LegalMovesForPlayer(gobot)
@@ -0,0 +1,196 @@
+/* Package gobotagent has the different kinds of players that can choose moves in a game:
+ * people at a console, the minimax search, other engines and a few simple opponents to test against.
+ */
+package gobotagent
+
+import (
+	"errors"
+	"math/rand"
+	"time"
+
+	"github.com/ktodaz/gobot/gobotcore"
+)
+
+var ErrNoMoves = errors.New("no legal moves")
+
+// An Agent chooses the move for the side to move in a game. It must not change the game
+type Agent interface {
+	ChooseMove(game *gobotcore.Game) (gobotcore.Move, error)
+}
+
+// Plays any legal move, each as likely as the others
+type RandomAgent struct {
+	rand *rand.Rand
+}
+
+func NewRandomAgent(seed int64) *RandomAgent {
+	return &RandomAgent{rand: rand.New(rand.NewSource(seed))}
+}
+
+func (agent *RandomAgent) ChooseMove(game *gobotcore.Game) (gobotcore.Move, error) {
+	moves := game.LegalMoves()
+	if len(moves) == 0 {
+		return gobotcore.Move{}, ErrNoMoves
+	}
+	return moves[agent.rand.Intn(len(moves))], nil
+}
+
+// Looks one move ahead and plays the move that leaves it with the most material. Ties are broken at random
+type GreedyAgent struct {
+	rand *rand.Rand
+}
+
+func NewGreedyAgent(seed int64) *GreedyAgent {
+	return &GreedyAgent{rand: rand.New(rand.NewSource(seed))}
+}
+
+func (agent *GreedyAgent) ChooseMove(game *gobotcore.Game) (gobotcore.Move, error) {
+	board := game.Board()
+	side := game.Turn()
+	var best gobotcore.Moves
+	var bestScore float32
+	for _, move := range game.LegalMoves() {
+		takenPiece := *board.MakeMoveAndGetTakenPiece(&move)
+		score := board.GetWeightedScoreForPlayer(&side)
+		board.RetractMove(&move, takenPiece)
+
+		if len(best) == 0 || score > bestScore {
+			best = gobotcore.Moves{move}
+			bestScore = score
+		} else if score == bestScore {
+			best = append(best, move)
+		}
+	}
+	if len(best) == 0 {
+		return gobotcore.Move{}, ErrNoMoves
+	}
+	return best[agent.rand.Intn(len(best))], nil
+}
+
+// Gobot's minimax search
+type MinimaxAgent struct {
+	Limits  gobotcore.SearchLimits
+	Options gobotcore.SearchOptions
+	// Keep searching while the opponent thinks, in the position after the reply the search expects
+	Ponder bool
+	// Score of the last move chosen, from the agent's side
+	LastScore float32
+	// True if the opponent played the expected reply before the last move, so it came from the ponder
+	LastPonderHit bool
+
+	ponder      *gobotcore.Ponder
+	ponderStop  chan struct{}
+	ponderMoves gobotcore.Moves // The game's moves up to the position the ponder searches
+}
+
+// Searches the way Gobot always has: 5 seconds a move, never less than depth 7
+func NewMinimaxAgent() *MinimaxAgent {
+	return &MinimaxAgent{Limits: gobotcore.SearchLimits{StartDepth: 7, MoveTime: 5 * time.Second}}
+}
+
+func (agent *MinimaxAgent) ChooseMove(game *gobotcore.Game) (gobotcore.Move, error) {
+	best, hit := agent.ponderResult(game)
+	if !hit {
+		board := game.Board()
+		side := game.Turn()
+		best = board.SearchWithOptions(&side, agent.Limits, agent.Options, nil, nil)
+	}
+	agent.LastPonderHit = hit
+	if len(best.PV()) == 0 {
+		return gobotcore.Move{}, ErrNoMoves
+	}
+	agent.LastScore = *best.Score()
+	if agent.Ponder {
+		agent.startPonder(game, best)
+	}
+	return *best.Move(), nil
+}
+
+// Returns the result of the ponder with true if the game reached the position it searched. Otherwise the ponder is thrown away
+func (agent *MinimaxAgent) ponderResult(game *gobotcore.Game) (gobotcore.ScoredMove, bool) {
+	ponder := agent.ponder
+	if ponder == nil {
+		return gobotcore.ScoredMove{}, false
+	}
+	history := game.History()
+	if len(history) != len(agent.ponderMoves) {
+		agent.StopPondering()
+		return gobotcore.ScoredMove{}, false
+	}
+	for i, played := range history {
+		if !played.Move.Equals(&agent.ponderMoves[i]) {
+			agent.StopPondering()
+			return gobotcore.ScoredMove{}, false
+		}
+	}
+
+	agent.ponder = nil
+	ponder.Hit()
+	return ponder.Wait(), true
+}
+
+// Ponders on the position after best's move and the reply it expects
+func (agent *MinimaxAgent) startPonder(game *gobotcore.Game, best gobotcore.ScoredMove) {
+	pv := best.PV()
+	if len(pv) < 2 {
+		return
+	}
+	board := game.Board()
+	side := game.Turn()
+	agent.ponderMoves = nil
+	for _, played := range game.History() {
+		agent.ponderMoves = append(agent.ponderMoves, played.Move)
+	}
+	for _, move := range pv[:2] {
+		board.MakeMoveAndGetTakenPiece(&move)
+		agent.ponderMoves = append(agent.ponderMoves, move)
+	}
+	agent.ponderStop = make(chan struct{})
+	agent.ponder = board.StartPonder(&side, agent.Limits, agent.Options, agent.ponderStop, nil)
+}
+
+// StopPondering throws away the search running on the opponent's time, if there is one. Call it when the game is over
+func (agent *MinimaxAgent) StopPondering() {
+	if agent.ponder == nil {
+		return
+	}
+	close(agent.ponderStop)
+	agent.ponder.Wait()
+	agent.ponder = nil
+}
+
+// Replays the moves of a recorded game. Both sides of a game can share one ScriptedAgent
+type ScriptedAgent struct {
+	moves gobotcore.Moves
+}
+
+func NewScriptedAgent(moves gobotcore.Moves) *ScriptedAgent {
+	return &ScriptedAgent{moves: moves}
+}
+
+// Replays the moves of the game record at path
+func LoadScriptedAgent(path string) (*ScriptedAgent, error) {
+	record, err := gobotcore.LoadGameRecord(path)
+	if err != nil {
+		return nil, err
+	}
+	var moves gobotcore.Moves
+	for _, played := range record.History() {
+		moves = append(moves, played.Move)
+	}
+	return NewScriptedAgent(moves), nil
+}
+
+// Plays the move the script has at this point of the game, as long as the game has followed the script so far
+func (agent *ScriptedAgent) ChooseMove(game *gobotcore.Game) (gobotcore.Move, error) {
+	history := game.History()
+	if len(history) >= len(agent.moves) {
+		return gobotcore.Move{}, errors.New("the script has no more moves")
+	}
+	for i, played := range history {
+		if !played.Move.Equals(&agent.moves[i]) {
+			return gobotcore.Move{}, errors.New("the game no longer follows the script")
+		}
+	}
+	return agent.moves[len(history)], nil
+}
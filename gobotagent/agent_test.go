@@ -0,0 +1,152 @@
+package gobotagent
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/ktodaz/gobot/gobotcore"
+)
+
+// Plays a game between two agents until it is over or maxMoves have been played
+func playGame(t *testing.T, gobot Agent, human Agent, maxMoves int) *gobotcore.Game {
+	game := gobotcore.NewGame(gobotcore.HUMAN)
+	agents := map[gobotcore.Player]Agent{gobotcore.GOBOT: gobot, gobotcore.HUMAN: human}
+	for i := 0; i < maxMoves && !game.IsOver(); i++ {
+		move, err := agents[game.Turn()].ChooseMove(game)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := game.MakeMove(move); err != nil {
+			t.Fatal("Agent played an illegal move " + move.ToString())
+		}
+	}
+	return game
+}
+
+func TestRandomAgent(t *testing.T) {
+	game := playGame(t, NewRandomAgent(1), NewRandomAgent(2), 200)
+	if len(game.History()) == 0 {
+		t.Error("Random agents should have played")
+	}
+	// The same seed plays the same moves
+	move1, _ := NewRandomAgent(7).ChooseMove(game)
+	move2, _ := NewRandomAgent(7).ChooseMove(game)
+	if !game.IsOver() && move1 != move2 {
+		t.Error("Same seed should choose the same move")
+	}
+}
+
+func TestGreedyAgent_TakesPiece(t *testing.T) {
+	// Human's bishop on C2 can take Gobot's rook on B1
+	game, err := gobotcore.NewGameFromPosition("K5/6/6/6/6/6/2b3/1R2k1 h")
+	if err != nil {
+		t.Fatal(err)
+	}
+	move, err := NewGreedyAgent(1).ChooseMove(game)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if move.ToString() != "C2B1" {
+		t.Error("Greedy agent should take the rook, played " + move.ToString())
+	}
+}
+
+func TestGreedyAgent_BeatsRandom(t *testing.T) {
+	game := playGame(t, NewGreedyAgent(1), NewRandomAgent(1), 300)
+	if winner, over := game.Winner(); over && winner != gobotcore.GOBOT {
+		t.Error("Greedy agent should not lose to a random one")
+	}
+}
+
+func TestMinimaxAgent(t *testing.T) {
+	agent := &MinimaxAgent{Limits: gobotcore.SearchLimits{Depth: 3}}
+	game := gobotcore.NewGame(gobotcore.HUMAN)
+	move, err := agent.ChooseMove(game)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := game.MakeMove(move); err != nil {
+		t.Error("Minimax agent played an illegal move " + move.ToString())
+	}
+}
+
+func TestMinimaxAgent_Ponder(t *testing.T) {
+	agent := &MinimaxAgent{Limits: gobotcore.SearchLimits{Depth: 4}, Ponder: true}
+	defer agent.StopPondering()
+	game := gobotcore.NewGame(gobotcore.HUMAN)
+
+	// Play the reply the agent expects, then one it doesn't
+	for _, expected := range []bool{true, false} {
+		move, err := agent.ChooseMove(game)
+		if err != nil {
+			t.Fatal(err)
+		}
+		game.MakeMove(move)
+		if agent.ponder == nil {
+			t.Fatal("Should be pondering after its move")
+		}
+		reply := agent.ponderMoves[len(agent.ponderMoves)-1]
+		if !expected {
+			for _, legal := range game.LegalMoves() {
+				if !legal.Equals(&reply) {
+					reply = legal
+					break
+				}
+			}
+		}
+		game.MakeMove(reply)
+
+		move, err = agent.ChooseMove(game)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if agent.LastPonderHit != expected {
+			t.Errorf("Ponder hit should be %t after reply %s", expected, reply.ToString())
+		}
+		if err := game.MakeMove(move); err != nil {
+			t.Error("Minimax agent played an illegal move " + move.ToString())
+		}
+		game.MakeMove(game.LegalMoves()[0])
+	}
+}
+
+func TestScriptedAgent(t *testing.T) {
+	script := NewScriptedAgent(gobotcore.Moves{
+		gobotcore.NewMoveFromString("C3C4"),
+		gobotcore.NewMoveFromString("D6D5"),
+	})
+	game := playGame(t, script, script, 2)
+	if history := game.History(); len(history) != 2 || history[1].Move.ToString() != "D6D5" {
+		t.Error("Script should have been replayed")
+	}
+	if _, err := script.ChooseMove(game); err == nil {
+		t.Error("Script should be out of moves")
+	}
+
+	game.Undo()
+	game.Undo()
+	game.MakeMove(gobotcore.NewMoveFromString("D3D4"))
+	if _, err := script.ChooseMove(game); err == nil {
+		t.Error("Game left the script")
+	}
+}
+
+func TestConsoleAgent(t *testing.T) {
+	var out bytes.Buffer
+	agent := NewConsoleAgent(strings.NewReader("hello\nC3C5\n\nC3C4\n"), &out)
+	game := gobotcore.NewGame(gobotcore.HUMAN)
+	move, err := agent.ChooseMove(game)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if move.ToString() != "C3C4" {
+		t.Error("Should return the first legal move, got " + move.ToString())
+	}
+	if !strings.Contains(out.String(), "Enter a move like C3C4") || !strings.Contains(out.String(), "A pawn only moves one square forward.") {
+		t.Error("Should explain bad input, got " + out.String())
+	}
+	if _, err := agent.ChooseMove(game); err == nil {
+		t.Error("Should fail when the input runs out")
+	}
+}
@@ -0,0 +1,47 @@
+package gobotagent
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/ktodaz/gobot/gobotcore"
+)
+
+// A person typing moves like C3C4. Illegal moves are explained and asked for again
+type ConsoleAgent struct {
+	in  *bufio.Reader
+	out io.Writer
+}
+
+func NewConsoleAgent(in io.Reader, out io.Writer) *ConsoleAgent {
+	return &ConsoleAgent{in: bufio.NewReader(in), out: out}
+}
+
+func (agent *ConsoleAgent) ChooseMove(game *gobotcore.Game) (gobotcore.Move, error) {
+	board := game.Board()
+	for {
+		fmt.Fprint(agent.out, game.Turn().Name()+", enter a move: ")
+		line, err := agent.in.ReadString('\n')
+		input := strings.TrimSpace(line)
+		if input == "" {
+			if err != nil {
+				return gobotcore.Move{}, errors.New("no more input")
+			}
+			continue
+		}
+
+		move, parseErr := gobotcore.ParseMove(input)
+		if parseErr != nil {
+			fmt.Fprintln(agent.out, "Enter a move like C3C4")
+			continue
+		}
+		if reason := board.WhyIllegal(&move, game.Turn()); reason != "" {
+			fmt.Fprintln(agent.out, reason)
+			continue
+		}
+		return move, nil
+	}
+}
@@ -0,0 +1,31 @@
+package gobotagent
+
+import (
+	"time"
+
+	"github.com/ktodaz/gobot/gobotcore"
+	"github.com/ktodaz/gobot/gobotproto"
+)
+
+// Another engine, spoken to over the text protocol of package gobotproto
+type ExternalAgent struct {
+	Client   *gobotproto.Client
+	MoveTime time.Duration
+}
+
+// Starts the engine process. Close it when the game is done
+func StartExternalAgent(moveTime time.Duration, command string, args ...string) (*ExternalAgent, error) {
+	client, err := gobotproto.StartClient(command, args...)
+	if err != nil {
+		return nil, err
+	}
+	return &ExternalAgent{Client: client, MoveTime: moveTime}, nil
+}
+
+func (agent *ExternalAgent) ChooseMove(game *gobotcore.Game) (gobotcore.Move, error) {
+	return agent.Client.BestMove(game, agent.MoveTime)
+}
+
+func (agent *ExternalAgent) Close() error {
+	return agent.Client.Close()
+}
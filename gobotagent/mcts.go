@@ -0,0 +1,288 @@
+package gobotagent
+
+import (
+	"math"
+	"math/rand"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/ktodaz/gobot/gobotcore"
+)
+
+const (
+	defaultExploration  = math.Sqrt2
+	defaultPlayouts     = 10000
+	defaultPlayoutDepth = 80
+)
+
+/* MCTSAgent chooses moves with Monte Carlo tree search (UCT) instead of minimax.
+ * Every iteration walks down the tree picking the child with the best upper confidence bound,
+ * adds one new node, plays a game out from it and counts the result for every node on the way back up.
+ * The move played the most is chosen. Several workers share one tree, and the tree is kept between moves
+ * so the part under the moves that were actually played doesn't have to be searched again.
+ */
+type MCTSAgent struct {
+	// Higher values try moves that look worse more often. Defaults to sqrt(2)
+	Exploration float64
+	// Playouts per move. With neither limit, 10000 playouts are run
+	Playouts int
+	MoveTime time.Duration
+	// Playouts that run at the same time. Defaults to GOMAXPROCS
+	Workers int
+	// Playouts prefer captures instead of playing uniformly random moves
+	Heuristic bool
+	// Playouts stop after this many moves and are scored by material. Defaults to 80
+	PlayoutDepth int
+	// Keep the tree between moves
+	ReuseTree bool
+
+	// Playouts run for the last move, and how many of them came from a reused tree
+	LastPlayouts int
+	LastReused   int
+
+	seed      int64
+	mu        sync.Mutex
+	root      *mctsNode
+	rootMoves gobotcore.Moves // Moves of the game that lead to root
+}
+
+type mctsNode struct {
+	move     gobotcore.Move   // The move that led here
+	player   gobotcore.Player // Who made move
+	parent   *mctsNode
+	children []*mctsNode
+	untried  gobotcore.Moves
+	visits   float64
+	wins     float64 // From player's point of view. Draws count half
+}
+
+func NewMCTSAgent(seed int64) *MCTSAgent {
+	return &MCTSAgent{Heuristic: true, ReuseTree: true, seed: seed}
+}
+
+func newMCTSNode(board *gobotcore.Board, toMove gobotcore.Player, move gobotcore.Move, parent *mctsNode) *mctsNode {
+	node := &mctsNode{move: move, player: *toMove.Opponent(), parent: parent}
+	moves := board.LegalMovesForPlayer(toMove)
+	if !board.IsGameOverForPlayer(&toMove, &moves) {
+		node.untried = moves
+	}
+	return node
+}
+
+func (agent *MCTSAgent) ChooseMove(game *gobotcore.Game) (gobotcore.Move, error) {
+	// A side can still have moves after a king was taken, but the game is over and the tree has nothing under the root
+	if game.IsOver() || len(game.LegalMoves()) == 0 {
+		return gobotcore.Move{}, ErrNoMoves
+	}
+	board := game.Board()
+	root := agent.findRoot(game, &board)
+	agent.LastReused = int(root.visits)
+
+	playouts, moveTime := agent.Playouts, agent.MoveTime
+	if playouts == 0 && moveTime == 0 {
+		playouts = defaultPlayouts
+	}
+	var deadline time.Time
+	if moveTime > 0 {
+		deadline = time.Now().Add(moveTime)
+	}
+	workers := agent.Workers
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+
+	var count int64
+	if len(root.children) == 0 {
+		// Make sure there is a move to choose even if the budget is tiny
+		agent.iterate(root, board, game.Turn(), rand.New(rand.NewSource(agent.seed)))
+		count++
+	}
+	var wait sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wait.Add(1)
+		random := rand.New(rand.NewSource(agent.seed + int64(i)))
+		go func() {
+			defer wait.Done()
+			for {
+				agent.mu.Lock()
+				done := (playouts > 0 && count >= int64(playouts)) || (!deadline.IsZero() && !time.Now().Before(deadline))
+				count++
+				agent.mu.Unlock()
+				if done {
+					return
+				}
+				agent.iterate(root, board, game.Turn(), random)
+			}
+		}()
+	}
+	wait.Wait()
+	agent.seed += int64(workers)
+	agent.LastPlayouts = int(root.visits) - agent.LastReused
+
+	if len(root.children) == 0 {
+		return gobotcore.Move{}, ErrNoMoves
+	}
+	best := root.children[0]
+	for _, child := range root.children {
+		if child.visits > best.visits {
+			best = child
+		}
+	}
+	return best.move, nil
+}
+
+// Returns the root for the game's position, reusing the old tree if the game has only gone further down it
+func (agent *MCTSAgent) findRoot(game *gobotcore.Game, board *gobotcore.Board) *mctsNode {
+	agent.mu.Lock()
+	defer agent.mu.Unlock()
+
+	history := game.History()
+	moves := make(gobotcore.Moves, len(history))
+	for i, played := range history {
+		moves[i] = played.Move
+	}
+	root := agent.root
+	if !agent.ReuseTree || root == nil || len(moves) < len(agent.rootMoves) {
+		root = nil
+	} else {
+		for i := range agent.rootMoves {
+			if !moves[i].Equals(&agent.rootMoves[i]) {
+				root = nil
+				break
+			}
+		}
+	}
+	for i := len(agent.rootMoves); root != nil && i < len(moves); i++ {
+		root = root.child(moves[i])
+	}
+
+	if root == nil {
+		root = newMCTSNode(board, game.Turn(), gobotcore.Move{}, nil)
+	}
+	root.parent = nil
+	agent.root = root
+	agent.rootMoves = moves
+	return root
+}
+
+func (node *mctsNode) child(move gobotcore.Move) *mctsNode {
+	for _, child := range node.children {
+		if child.move.Equals(&move) {
+			return child
+		}
+	}
+	return nil
+}
+
+// One iteration of selection, expansion, playout and backpropagation
+func (agent *MCTSAgent) iterate(root *mctsNode, board gobotcore.Board, toMove gobotcore.Player, random *rand.Rand) {
+	agent.mu.Lock()
+	node := root
+	// Visits are counted on the way down, so other workers see the path as already tried and spread out
+	node.visits++
+	for len(node.untried) == 0 && len(node.children) > 0 {
+		node = node.selectChild(agent.exploration())
+		board.MakeMoveAndGetTakenPiece(&node.move)
+		toMove = *toMove.Opponent()
+		node.visits++
+	}
+	if len(node.untried) > 0 {
+		i := random.Intn(len(node.untried))
+		move := node.untried[i]
+		node.untried = append(node.untried[:i], node.untried[i+1:]...)
+		board.MakeMoveAndGetTakenPiece(&move)
+		toMove = *toMove.Opponent()
+		child := newMCTSNode(&board, toMove, move, node)
+		node.children = append(node.children, child)
+		node = child
+		node.visits++
+	}
+	agent.mu.Unlock()
+
+	gobotScore := agent.playout(board, toMove, random)
+
+	agent.mu.Lock()
+	for ; node != nil; node = node.parent {
+		if node.player == gobotcore.GOBOT {
+			node.wins += gobotScore
+		} else {
+			node.wins += 1 - gobotScore
+		}
+	}
+	agent.mu.Unlock()
+}
+
+func (agent *MCTSAgent) exploration() float64 {
+	if agent.Exploration > 0 {
+		return agent.Exploration
+	}
+	return defaultExploration
+}
+
+// UCT: the child's win rate plus a bonus for children that have been tried less
+func (node *mctsNode) selectChild(exploration float64) *mctsNode {
+	var best *mctsNode
+	bestValue := math.Inf(-1)
+	logVisits := math.Log(node.visits)
+	for _, child := range node.children {
+		value := child.wins/child.visits + exploration*math.Sqrt(logVisits/child.visits)
+		if value > bestValue {
+			best, bestValue = child, value
+		}
+	}
+	return best
+}
+
+// Plays the game out and returns 1 if Gobot wins, 0 if it loses and 0.5 for a draw.
+// Games that run too long are scored by who has more material
+func (agent *MCTSAgent) playout(board gobotcore.Board, toMove gobotcore.Player, random *rand.Rand) float64 {
+	depth := agent.PlayoutDepth
+	if depth <= 0 {
+		depth = defaultPlayoutDepth
+	}
+	for i := 0; i < depth; i++ {
+		moves := board.LegalMovesForPlayer(toMove)
+		if board.IsGameOverForPlayer(&toMove, &moves) {
+			if toMove == gobotcore.GOBOT {
+				return 0
+			}
+			return 1
+		}
+		move := agent.playoutMove(&board, moves, toMove, random)
+		board.MakeMoveAndGetTakenPiece(&move)
+		toMove = *toMove.Opponent()
+	}
+
+	gobot := gobotcore.Player(gobotcore.GOBOT)
+	score := board.GetWeightedScoreForPlayer(&gobot)
+	switch {
+	case score > 0:
+		return 1
+	case score < 0:
+		return 0
+	}
+	return 0.5
+}
+
+// Heuristic playouts always take the king and usually take the most valuable piece they can
+func (agent *MCTSAgent) playoutMove(board *gobotcore.Board, moves gobotcore.Moves, toMove gobotcore.Player, random *rand.Rand) gobotcore.Move {
+	if !agent.Heuristic {
+		return moves[random.Intn(len(moves))]
+	}
+	var best gobotcore.Move
+	var bestWeight float32
+	for _, move := range moves {
+		victim := board.PieceAt(move.To())
+		if !victim.IsOwnedBy(toMove.Opponent()) {
+			continue
+		}
+		if weight := victim.Weight(); weight > bestWeight {
+			best, bestWeight = move, weight
+		}
+	}
+	if bestWeight >= 1000 || (bestWeight > 0 && random.Float64() < 0.8) {
+		return best
+	}
+	return moves[random.Intn(len(moves))]
+}
@@ -0,0 +1,91 @@
+package gobotagent
+
+import (
+	"testing"
+
+	"github.com/ktodaz/gobot/gobotcore"
+)
+
+func TestMCTSAgent_TakesKing(t *testing.T) {
+	// Human's rook on B5 can take Gobot's king on B8. Otherwise Gobot's rook on E8 takes Human's king
+	game, err := gobotcore.NewGameFromPosition("1K2R1/6/6/1r4/6/6/6/4k1 h")
+	if err != nil {
+		t.Fatal(err)
+	}
+	agent := NewMCTSAgent(1)
+	agent.Playouts = 2000
+	move, err := agent.ChooseMove(game)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if move.ToString() != "B5B8" {
+		t.Error("Should take the king, played " + move.ToString())
+	}
+}
+
+func TestMCTSAgent_KingTaken(t *testing.T) {
+	// Gobot's king was taken, but it still has pieces that could move
+	game, err := gobotcore.NewGameFromPosition("6/NBR3/6/6/6/6/6/4k1 g")
+	if err != nil {
+		t.Fatal(err)
+	}
+	agent := NewMCTSAgent(1)
+	agent.Playouts = 100
+	if _, err := agent.ChooseMove(game); err != ErrNoMoves {
+		t.Error("Should have no move once the game is over, got", err)
+	}
+}
+
+func TestMCTSAgent_Budget(t *testing.T) {
+	agent := NewMCTSAgent(1)
+	agent.Playouts = 300
+	agent.Workers = 4
+	agent.ReuseTree = false
+	game := gobotcore.NewGame(gobotcore.HUMAN)
+	if _, err := agent.ChooseMove(game); err != nil {
+		t.Fatal(err)
+	}
+	if agent.LastPlayouts != 300 {
+		t.Error("Should run exactly the playout budget, ran", agent.LastPlayouts)
+	}
+}
+
+func TestMCTSAgent_ReusesTree(t *testing.T) {
+	agent := NewMCTSAgent(1)
+	agent.Playouts = 2000
+	game := gobotcore.NewGame(gobotcore.HUMAN)
+	for i := 0; i < 2; i++ {
+		move, err := agent.ChooseMove(game)
+		if err != nil {
+			t.Fatal(err)
+		}
+		game.MakeMove(move)
+		// The opponent replies with the move the tree expects most
+		reply := agent.root.child(move)
+		var best *mctsNode
+		for _, child := range reply.children {
+			if best == nil || child.visits > best.visits {
+				best = child
+			}
+		}
+		game.MakeMove(best.move)
+	}
+	if agent.LastReused == 0 {
+		t.Error("Second move should start from the old tree")
+	}
+
+	agent.ReuseTree = false
+	agent.ChooseMove(game)
+	if agent.LastReused != 0 {
+		t.Error("Tree should not be reused when reuse is off")
+	}
+}
+
+func TestMCTSAgent_BeatsRandom(t *testing.T) {
+	mcts := NewMCTSAgent(1)
+	mcts.Playouts = 300
+	game := playGame(t, mcts, NewRandomAgent(1), 200)
+	if winner, over := game.Winner(); over && winner != gobotcore.GOBOT {
+		t.Error("MCTS should not lose to a random agent")
+	}
+}
@@ -0,0 +1,124 @@
+package gobotcore
+
+/* The sums of the network's first layer for a board, kept up to date as the search makes and takes back moves.
+ * A move only takes its piece off one square, puts it morphed on another and maybe takes a piece there,
+ * so updating the sums is a few additions of a column of weights instead of going over the whole board.
+ * Each goroutine of a search works on a board of its own, so the search keeps an accumulator for every board it sees,
+ * and drops it when the goroutine is done with the board
+ */
+type accumulator struct {
+	board Board // The board the sums are for, to catch a board that changed behind the accumulator's back
+	sums  []int16
+}
+
+func (network *Network) newAccumulator(board *Board) *accumulator {
+	acc := &accumulator{sums: make([]int16, network.hidden1)}
+	acc.refresh(network, board)
+	return acc
+}
+
+// Works out the sums from scratch
+func (acc *accumulator) refresh(network *Network, board *Board) {
+	copy(acc.sums, network.quantBiases1)
+	var row, col int8
+	for row = 0; row < boardRows; row++ {
+		for col = 0; col < boardCols; col++ {
+			location := Location{row: row, col: col}
+			if piece := board.PieceAt(&location); !piece.IsEmpty() {
+				acc.add(network, piece, location)
+			}
+		}
+	}
+	acc.board = *board
+}
+
+func (acc *accumulator) add(network *Network, piece Piece, location Location) {
+	weights := network.quantWeights1[featureIndex(piece, location)*network.hidden1:]
+	for i := range acc.sums {
+		acc.sums[i] += weights[i]
+	}
+}
+
+func (acc *accumulator) remove(network *Network, piece Piece, location Location) {
+	weights := network.quantWeights1[featureIndex(piece, location)*network.hidden1:]
+	for i := range acc.sums {
+		acc.sums[i] -= weights[i]
+	}
+}
+
+// The network's score of the board from Gobot's point of view
+func (acc *accumulator) score(network *Network) float32 {
+	activations := make([]float32, len(acc.sums))
+	for i, sum := range acc.sums {
+		activations[i] = clippedReLU(float32(sum) / firstLayerScale)
+	}
+	return networkScore(network.output(activations))
+}
+
+// The accumulator of board, made on the first call for it
+func (control *searchControl) accumulator(board *Board) *accumulator {
+	network := control.options.Network
+	value, ok := control.accumulators.Load(board)
+	if !ok {
+		acc := network.newAccumulator(board)
+		control.accumulators.Store(board, acc)
+		return acc
+	}
+	acc := value.(*accumulator)
+	if acc.board != *board {
+		acc.refresh(network, board)
+	}
+	return acc
+}
+
+// Drops the accumulator of a board copy once its search is over. Otherwise every copy made during the search would be kept until the end
+func (control *searchControl) forgetBoard(board *Board) {
+	if control.options.Network != nil {
+		control.accumulators.Delete(board)
+	}
+}
+
+// Makes the move on board like MakeMoveAndGetTakenPiece, and updates the board's accumulator when the search uses a network
+func (control *searchControl) makeMove(board *Board, move *Move) Piece {
+	network := control.options.Network
+	if network == nil {
+		return *board.MakeMoveAndGetTakenPiece(move)
+	}
+	acc := control.accumulator(board)
+	piece := board.PieceAt(&move.from)
+	takenPiece := *board.MakeMoveAndGetTakenPiece(move)
+	acc.remove(network, piece, move.from)
+	if !takenPiece.IsEmpty() {
+		acc.remove(network, takenPiece, move.to)
+	}
+	acc.add(network, board.PieceAt(&move.to), move.to)
+	acc.board = *board
+	return takenPiece
+}
+
+// Takes the move back like RetractMove, and updates the board's accumulator when the search uses a network
+func (control *searchControl) retractMove(board *Board, move *Move, takenPiece Piece) {
+	network := control.options.Network
+	if network == nil {
+		board.RetractMove(move, takenPiece)
+		return
+	}
+	acc := control.accumulator(board)
+	movedPiece := board.PieceAt(&move.to)
+	board.RetractMove(move, takenPiece)
+	acc.remove(network, movedPiece, move.to)
+	if !takenPiece.IsEmpty() {
+		acc.add(network, takenPiece, move.to)
+	}
+	acc.add(network, board.PieceAt(&move.from), move.from)
+	acc.board = *board
+}
+
+// The network's score of the board for player
+func (control *searchControl) networkScore(board *Board, player *Player) float32 {
+	score := control.accumulator(board).score(control.options.Network)
+	if *player == HUMAN {
+		return -score
+	}
+	return score
+}
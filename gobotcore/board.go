@@ -2,26 +2,25 @@ package gobotcore
 
 import (
 	"fmt"
-	"sort"
 	"strings"
 	"time"
 )
 
-type Board [boardRows][boardCols]Piece
+// The squares past the edge of a variant's board are always empty, see variant.go
+type Board [maxBoardRows][maxBoardCols]Piece
 
 const (
 	// Duration of the move time
 	moveTime time.Duration = 5 * time.Second
 
-	// Board info
-	boardCols int8 = 6
-	boardRows int8 = 8
-
 	//Minimax
 	bestMax float32 = 9999999.0
 	bestMin float32 = -9999999.0
 	winMax  float32 = 2000000.0
 	winMin  float32 = -2000000.0
+
+	// Width of the windows that only test whether a move beats a bound
+	zeroWindowWidth float32 = 0.01
 )
 
 var (
@@ -29,7 +28,6 @@ var (
 	curMaxDepth   int8
 	stopSearch    bool
 	debug         bool = true
-	timeOver      bool
 	numGoRoutines int
 )
 
@@ -46,107 +44,57 @@ func NewEmptyBoard() Board {
 	return emptyBoard
 }
 
+// The start position of the variant, see SetVariant. For the standard one:
+//
+//	8   - K - - - -
+//	7   N B R R B N
+//	6   - - P P - -
+//	5   - - - - - -
+//	4   - - - - - -
+//	3   - - p p - -
+//	2   n b r r b n
+//	1   - - - - k -
+//
+//	    A B C D E F
 func NewDefaultBoard() Board {
-	/*Creates a board like so:
-	7   - K - - - -
-	6   N B R R B N
-	5   - - P P - -
-	4   - - - - - -
-	3   - - - - - -
-	2   - - p p - -
-	1   n n r r b n
-	0   - - - - k -
-
-		A B C D E F
-	*/
-	defaultBoard := Board{}
-	// Gobot pieces
-	defaultBoard[7][1] = KING_GOB
-	defaultBoard[6][0] = KNIGHT_GOB
-	defaultBoard[6][1] = BISHOP_GOB
-	defaultBoard[6][2] = ROOK_GOB
-	defaultBoard[6][3] = ROOK_GOB
-	defaultBoard[6][4] = BISHOP_GOB
-	defaultBoard[6][5] = KNIGHT_GOB
-	defaultBoard[5][2] = PAWN_GOB
-	defaultBoard[5][3] = PAWN_GOB
-	// Human pieces
-	defaultBoard[2][2] = PAWN_HUM
-	defaultBoard[2][3] = PAWN_HUM
-	defaultBoard[1][0] = KNIGHT_HUM
-	defaultBoard[1][1] = BISHOP_HUM
-	defaultBoard[1][2] = ROOK_HUM
-	defaultBoard[1][3] = ROOK_HUM
-	defaultBoard[1][4] = BISHOP_HUM
-	defaultBoard[1][5] = KNIGHT_HUM
-	defaultBoard[0][4] = KING_HUM
-	return defaultBoard
+	return startBoard
 }
 
+// Reads a board drawn like above. Every line that starts with a row number is a row, the top one first.
+// The other lines, like the column letters, are skipped
 func NewBoardFromString(boardString string) Board {
-	/*Board format is like so:
-	7   - K - - - -
-	6   N B R R B N
-	5   - - P P - -
-	4   - - - - - -
-	3   - - - - - -
-	2   - - p p - -
-	1   n n r r b n
-	0   - - - - k -
-
-	    A B C D E F
-	*/
 	board := Board{}
-	boardStringRows := strings.Split(boardString, "\n")
-	if len(boardStringRows) != 10 {
-		panic("Incorrect input row size")
-	}
-	if len(boardStringRows[0]) != 15 {
-		panic("Incorrect input col size")
-	}
-
-	// These are not the magic numbers you're looking for
-	for i := len(boardStringRows) - 3; i >= 0; i-- {
-		boardStringRow := boardStringRows[i]
-		for j := 4; j < len(boardStringRow); j = j + 2 {
-			colIndex := (j - 4) / 2
-			rowIndex := 7 - i
-			board[rowIndex][colIndex] = GetPieceByName(string(boardStringRow[j]))
+	var row int8 = boardRows
+	for _, line := range strings.Split(boardString, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 || AtoiEZPZ(fields[0]) < 1 {
+			continue
+		}
+		row--
+		if row < 0 {
+			panic("Incorrect input row size")
+		}
+		if len(fields)-1 != int(boardCols) {
+			panic("Incorrect input col size")
 		}
+		for col, name := range fields[1:] {
+			board[row][col] = GetPieceByName(name)
+		}
+	}
+	if row != 0 {
+		panic("Incorrect input row size")
 	}
 	return board
 }
 
+// Prints the board with the renderer set by SetRenderer, see Renderer.Render
 func (board *Board) PrintBoard() {
-	/*Prints board like so
-	7   - K - - - -
-	6   N B R R B N
-	5   - - P P - -
-	4   - - - - - -
-	3   - - - - - -
-	2   - - p p - -
-	1   n n r r b n
-	0   - - - - k -
-
-	    A B C D E F
-	*/
-	fmt.Println()
-	var row, col int8
-	for row = boardRows - 1; row >= 0; row-- {
-		fmt.Print(row+1, "   ")
-		for col = 0; col < boardCols; col++ {
-			// Print piece
-			val := &board[row][col]
-			fmt.Print(val.GetName())
-
-			// Add space and/or newline
-			fmt.Print(" ")
-			if col == boardCols-1 {
-				fmt.Println()
-			}
-		}
-	}
-	fmt.Println("\n    A B C D E F\n")
+	defaultRenderer.Print(board)
+}
+
+// Number of columns and rows on the board
+func BoardSize() (int8, int8) {
+	return boardCols, boardRows
 }
 
 func (board *Board) PieceAt(location *Location) Piece {
@@ -183,7 +131,10 @@ func (board *Board) SetPieceAtLocation(location *Location, pieceToSet Piece) {
 }
 
 func (board *Board) IsValidHumanMove(move *Move) bool {
-	player := Player(HUMAN)
+	return board.IsValidMoveForPlayer(move, HUMAN)
+}
+
+func (board *Board) IsValidMoveForPlayer(move *Move, player Player) bool {
 	moves := board.LegalMovesForPlayer(player)
 	return move.IsContainedIn(&moves)
 }
@@ -199,319 +150,316 @@ func SetDebug(bool bool) {
 // There is some performance impact by creating many goRoutines because we are creating copies of the board object
 // Therefore, we end the goroutine recursion at the second level, and switch to an iterative approach
 func (board *Board) MinimaxMulti(player *Player, depth *int8) ScoredMove {
+	limits := SearchLimits{StartDepth: *depth, MoveTime: moveTime}
+	return board.Search(player, limits, nil, nil)
+}
+
+// Searches the root moves to a fixed depth with the window alpha to beta. This is one iteration of the iterative deepening in Search.
+// Also returns every root move with its score. Only moves scoring within the search's Randomness of the best have exact scores.
+// If a move scores beta or more the iteration stops early, since Search has to widen the window and search again anyway
+// The moves are reordered in place
+func (board *Board) minimaxRoot(player *Player, playerMoves Moves, depth int8, alpha float32, beta float32, control *searchControl) (ScoredMove, []ScoredMove) {
 	best := ScoredMove{score: bestMin}
-	playerMoves := board.LegalMovesForPlayer(*player)
-	opponent := player.Opponent()
-	numGoRoutines = 0
+	scored := make([]ScoredMove, 0, len(playerMoves))
+	if len(playerMoves) == 0 {
+		return best, scored
+	}
+	control.orderMoves(board, playerMoves, 0)
 
-	// This go channel is the communication link between the goRoutines and this function
-	// Go primarily uses message passing between goRoutines and their parents
-	scoreChan := make(chan ScoredMove)
+	// Moves near the best need exact scores, so alpha trails the best score by the margin
+	margin := control.options.Randomness
+	record := func(cur ScoredMove) {
+		scored = append(scored, cur)
+		if cur.score > best.score {
+			best = cur
+			if best.score-margin > alpha {
+				alpha = best.score - margin
+			}
+		}
+	}
 
-	// Channel that will carry on to the goRoutines to tell them to stop early if needed
-	timeOver = false
-	go func() {
-		time.Sleep(moveTime)
-		timeOver = true
-	}()
+	// Closed when the root fails high, so the goroutines still searching stop too
+	stop := make(chan struct{})
 
-	fmt.Printf("Going to depth %d\n", int(*depth))
+	if debug {
+		fmt.Printf("Going to depth %d\n", int(depth))
+	}
 
-	for _, move := range playerMoves {
-		boardCopy := *board
-		boardCopy.MakeMoveAndGetTakenPiece(&move)
+	// The first move was the best of the last iteration, so it is searched alone to get a good bound for the others
+	record(board.searchRootMove(player, playerMoves[0], depth, alpha, beta, false, stop, len(playerMoves), control))
+	if best.score >= beta || len(playerMoves) == 1 {
+		return best, scored
+	}
 
-		scoredMove := ScoredMove{move: move}
+	// A deterministic search takes the moves one at a time in order, so ties always go to the move ordered first
+	if control.singleThreaded() {
+		for _, move := range playerMoves[1:] {
+			record(board.searchRootMove(player, move, depth, alpha, beta, true, stop, len(playerMoves), control))
+			if best.score >= beta || control.isOver() {
+				break
+			}
+		}
+		return best, scored
+	}
 
-		go func() { // Initiate a goRoutine.
-			// &bestScore passes a pointer to the ever-changing bestScore variable.
-			// This will ensure that no matter what stage the goRoutine is in it has the ability to
-			// see what its parents best score is.
-			curScore := boardCopy.MinMulti(opponent, depth, &best.score)
-			scoredMove.score = curScore
-			scoreChan <- scoredMove // Pass the scoredMove object back to the scoreChan channel
-		}()
+	// This go channel is the communication link between the goRoutines and this function
+	// Go primarily uses message passing between goRoutines and their parents
+	scoreChan := make(chan ScoredMove, len(playerMoves)-1)
+	for _, move := range playerMoves[1:] {
+		go func(move Move, bound float32) { // Initiate a goRoutine.
+			scoreChan <- board.searchRootMove(player, move, depth, bound, beta, true, stop, len(playerMoves), control)
+		}(move, alpha)
 	}
 
-	for i := 0; i < len(playerMoves); i++ { // Loop until all goRoutines are done
+	// Every goRoutine is waited for, even after a fail high, so none is still reading the search state when the next iteration changes it
+	failedHigh := false
+	for i := 1; i < len(playerMoves); i++ { // Loop until all goRoutines are done
 		cur := <-scoreChan // Execution will halt here and will wait until next goRoutine is done
-		if cur.score > best.score {
-			/*if debug {
-				fmt.Print("NumGoRoutines: ")
-				fmt.Println(runtime.NumGoroutine())
-			}*/
-
-			best.move = cur.move
-			best.score = cur.score
+		if failedHigh {
+			continue
 		}
-	}
-
-	if !timeOver {
-		newDepth := *depth + 1
-		newBest := board.MinimaxMulti(player, &newDepth)
-		if newBest.score > best.score {
-			best = newBest
+		record(cur)
+		if best.score >= beta {
+			failedHigh = true
+			close(stop)
 		}
 	}
 
-	return best
+	return best, scored
 }
 
-// I Found that ending the goroutine recursion at the second level is the most optimal. That is why there is no MaxMulti function.
-func (board *Board) MinMulti(player *Player, depth *int8, parentsBestScore *float32) float32 {
-	bestScore := bestMax
-	//	var bestMove Move
-	playerMoves := board.LegalMovesForPlayer(*player)
-	scoreChan := make(chan ScoredMove)
-	stopChan := make(chan struct{})
-	newDepth := *depth - 1
-	//var bestMove Move
+// Makes the root move on a copy of the board and searches it. With zeroWindow the move is first only tested against alpha,
+// and only searched with the full window if it beats it
+func (board *Board) searchRootMove(player *Player, move Move, depth int8, alpha float32, beta float32, zeroWindow bool, stop <-chan struct{}, numMoves int, control *searchControl) ScoredMove {
+	boardCopy := *board
+	boardCopy.MakeMoveAndGetTakenPiece(&move)
+	defer control.forgetBoard(&boardCopy)
+	opponent := player.Opponent()
+	control.options.Trace.setMove(move)
 
-	if board.IsGameOverForPlayer(player, &playerMoves) {
-		return winMax
+	pv := Moves{}
+	if zeroWindow {
+		score := -boardCopy.searchRootChild(opponent, depth, zeroWindowAlpha(-alpha), -alpha, stop, numMoves, control, &pv)
+		if score <= alpha || score >= beta {
+			return ScoredMove{move: move, score: score, pv: append(Moves{move}, pv...)}
+		}
+		pv = Moves{}
 	}
+	score := -boardCopy.searchRootChild(opponent, depth, -beta, -alpha, stop, numMoves, control, &pv)
+	return ScoredMove{move: move, score: score, pv: append(Moves{move}, pv...)}
+}
 
-	if newDepth == 0 {
-		return board.GetWeightedScoreForPlayer(player)
+// A deterministic or traced search stays on one goroutine, so it searches the position after a root move with Negamax instead of NegamaxMulti
+func (board *Board) searchRootChild(player *Player, depth int8, alpha float32, beta float32, stop <-chan struct{}, numMoves int, control *searchControl, pv *Moves) float32 {
+	if control.singleThreaded() {
+		return board.Negamax(player, depth, 1, alpha, beta, stop, numMoves, control, pv, true)
 	}
+	return board.NegamaxMulti(player, depth, 1, alpha, beta, stop, numMoves, control, pv)
+}
 
-	for _, move := range playerMoves {
-		boardCopy := *board
-		boardCopy.MakeMoveAndGetTakenPiece(&move)
+// Searches the moves of a node at the same time, so that the work is spread over every core.
+// Like the root, the first move is searched alone to get a bound, and the others are tested against it with a zero window (young brothers wait).
+// I Found that ending the goroutine recursion at the second level is the most optimal, so the moves are searched with Negamax
+func (board *Board) NegamaxMulti(player *Player, depth int8, ply int, alpha float32, beta float32, parentStopChan <-chan struct{}, numParentMoves int, control *searchControl, pv *Moves) float32 {
+	control.countNode()
+	playerMoves := board.LegalMovesForPlayer(*player)
 
-		scoredMove := ScoredMove{move: move}
-		go func() {
-			curScore := boardCopy.MaxMulti(player.Opponent(), &newDepth, &bestScore, stopChan, len(playerMoves))
-			scoredMove.score = curScore
-			scoreChan <- scoredMove
-		}()
+	if board.IsGameOverForPlayer(player, &playerMoves) {
+		return lossScore(ply)
 	}
 
-	for i := 0; i < len(playerMoves); i++ {
-
-		cur := <-scoreChan
-		/*if debug {
-			fmt.Print("NumGoRoutines: ")
-			fmt.Println(runtime.NumGoroutine())
-		}*/
+	// Mate distance pruning: a quicker win or slower loss found elsewhere can't be beaten from here
+	if alpha, beta = mateDistanceWindow(ply, alpha, beta); alpha >= beta {
+		return alpha
+	}
 
-		if cur.score < bestScore {
-			bestScore = cur.score
-			//bestMove = cur.move
-		}
+	newDepth := depth - 1
 
-		// alpha-beta pruning
-		if cur.score < *parentsBestScore {
-			/*if debug {
-				fmt.Printf("Stopping goRoutines because curScore %f is less than parents best score %f\n", bestScore, *parentsBestScore)
-				fmt.Print("NumGoRoutines: ")
-				fmt.Println(runtime.NumGoroutine())
-			}*/
-			close(stopChan) // Send message to all the goRoutines to tell them to stop. We don't care about their output now
-			return bestScore
-		}
+	if newDepth == 0 {
+		return board.quiesce(player, ply, alpha, beta, playerMoves, numParentMoves, control)
 	}
 
-	/*if debug {
-		fmt.Printf("MIN%d: Found bestscore %f moves left %d with move %s \n", depth, bestScore, len(playerMoves), bestMove.ToString())
-	}*/
-
-	return bestScore
-}
+	control.orderMoves(board, playerMoves, ply)
+	opponent := player.Opponent()
 
-func (board *Board) MaxMulti(player *Player, depth *int8, parentsBestScore *float32, parentStopChan <-chan struct{}, numParentMoves int) float32 {
-	bestScore := bestMin
-	//	var bestMove Move
-	playerMoves := board.LegalMovesForPlayer(*player)
-	scoreChan := make(chan ScoredMove)
+	// Closed to tell all the goRoutines to stop. We don't care about their output then
 	stopChan := make(chan struct{})
-	newDepth := *depth - 1
-	//var bestMove Move
 
-	if board.IsGameOverForPlayer(player, &playerMoves) {
-		return winMin
+	first := playerMoves[0]
+	boardCopy := *board
+	boardCopy.MakeMoveAndGetTakenPiece(&first)
+	childPV := Moves{}
+	bestScore := -boardCopy.Negamax(opponent, newDepth, ply+1, -beta, -alpha, stopChan, len(playerMoves), control, &childPV, true)
+	control.forgetBoard(&boardCopy)
+	*pv = append(Moves{first}, childPV...)
+	if bestScore >= beta || control.isOver() {
+		return bestScore
 	}
-
-	if newDepth == 0 {
-		return float32(len(playerMoves)*2) - float32(numParentMoves*2) + board.GetWeightedScoreForPlayer(player)
+	if bestScore > alpha {
+		alpha = bestScore
 	}
 
-	for _, move := range playerMoves {
+	scoreChan := make(chan ScoredMove, len(playerMoves)-1)
+	for _, move := range playerMoves[1:] {
 		boardCopy := *board
 		boardCopy.MakeMoveAndGetTakenPiece(&move)
 
 		scoredMove := ScoredMove{move: move}
-		go func() {
-			// Call min because we are done doing recursion with goRoutines
-			curScore := boardCopy.Min(player.Opponent(), &newDepth, &bestScore, stopChan, len(playerMoves))
-			scoredMove.score = curScore
+		go func(bound float32) {
+			defer control.forgetBoard(&boardCopy)
+			scoredMove.score = -boardCopy.Negamax(opponent, newDepth, ply+1, zeroWindowAlpha(-bound), -bound, stopChan, len(playerMoves), control, &scoredMove.pv, true)
+			if scoredMove.score > bound && scoredMove.score < beta {
+				scoredMove.pv = Moves{}
+				scoredMove.score = -boardCopy.Negamax(opponent, newDepth, ply+1, -beta, -bound, stopChan, len(playerMoves), control, &scoredMove.pv, true)
+			}
 			scoreChan <- scoredMove
-		}()
+		}(alpha)
 	}
 
-	for i := 0; i < len(playerMoves); i++ {
+	// The goRoutines are always waited for, so none of them outlives the search that started them
+	stopped := false
+	stop := func() {
+		if !stopped {
+			stopped = true
+			close(stopChan)
+		}
+	}
+	for i := 1; i < len(playerMoves); {
 		select {
-		default:
-			cur := <-scoreChan
-			/*if debug {
-				fmt.Print("NumGoRoutines: ")
-				fmt.Println(runtime.NumGoroutine())
-			}*/
-
+		case cur := <-scoreChan:
+			i++
+			if stopped {
+				continue
+			}
 			if cur.score > bestScore {
 				bestScore = cur.score
-				//bestMove = cur.move
+				*pv = append(Moves{cur.move}, cur.pv...)
 			}
 
 			// alpha-beta pruning
-			if cur.score > *parentsBestScore {
-				/*if debug {
-					fmt.Printf("Stopping goRoutines because curScore %f is less than parents best score %f\n", bestScore, *parentsBestScore)
-					fmt.Print("NumGoRoutines: ")
-					fmt.Println(runtime.NumGoroutine())
-				}*/
-				close(stopChan) // Send message to all the goRoutines to tell them to stop. We don't care about their output now
-				return bestScore
+			if bestScore >= beta {
+				stop()
 			}
 		case <-parentStopChan:
-			// Parent told us to stop execution.. must have been a bad child
-			/*if debug {
-				fmt.Printf("Max%d: stopped execution\n", newDepth)
-			}*/
-			close(stopChan)
-			return bestScore // Returning this score shouldn't do anything
+			// Parent told us to stop execution.. must have been a bad child. Returning this score shouldn't do anything
+			stop()
+			parentStopChan = nil
 		}
-
 	}
-
-	/*if debug {
-		fmt.Printf("MAX%d: Found bestscore %f moves left %d with move %s \n", depth, bestScore, len(playerMoves), bestMove.ToString())
-	}*/
+	stop()
 
 	return bestScore
 }
 
-func (board *Board) Max(player *Player, depth *int8, parentsBestScore *float32, stopChan <-chan struct{}, numParentMoves int) float32 {
+/* Negamax returns the score of the board for player, searching depth - 1 more moves. The node is ply moves from the root.
+ * Scores are from the point of view of the player to move, so a child's score is negated for its parent.
+ * Only scores between alpha and beta are exact. A score of alpha or less means the true score is at most that much,
+ * and a score of beta or more means it is at least that much. Either way the parent won't pick the move.
+ * allowNull is false right after a null move, so that two passes in a row can't cut the search short
+ */
+func (board *Board) Negamax(player *Player, depth int8, ply int, alpha float32, beta float32, stopChan <-chan struct{}, numParentMoves int, control *searchControl, pv *Moves, allowNull bool) float32 {
+	trace := control.options.Trace
+	if trace == nil {
+		return board.negamax(player, depth, ply, alpha, beta, stopChan, numParentMoves, control, pv, allowNull)
+	}
+	trace.enter(depth, ply, alpha, beta)
+	score := board.negamax(player, depth, ply, alpha, beta, stopChan, numParentMoves, control, pv, allowNull)
+	trace.exit(score, control.isOver())
+	return score
+}
+
+func (board *Board) negamax(player *Player, depth int8, ply int, alpha float32, beta float32, stopChan <-chan struct{}, numParentMoves int, control *searchControl, pv *Moves, allowNull bool) float32 {
+	control.countNode()
 	playerMoves := board.LegalMovesForPlayer(*player)
 
 	if board.IsGameOverForPlayer(player, &playerMoves) {
-		return winMin
+		return lossScore(ply)
 	}
 
-	newDepth := *depth - 1
-
-	if newDepth == 0 {
-		return float32(len(playerMoves)*2) - float32(numParentMoves*2) + board.GetWeightedScoreForPlayer(player)
+	// Mate distance pruning: a quicker win or slower loss found elsewhere can't be beaten from here
+	if alpha, beta = mateDistanceWindow(ply, alpha, beta); alpha >= beta {
+		return alpha
 	}
 
-	//var bestMove Move
-	bestScore := bestMin
-	sort.Sort(playerMoves)
-
-	for _, move := range playerMoves {
-		takenPiece := *board.MakeMoveAndGetTakenPiece(&move)
-		curScore := board.Min(player.Opponent(), &newDepth, &bestScore, stopChan, len(playerMoves))
+	newDepth := depth - 1
 
-		select {
-		default:
-			if curScore > bestScore {
-				bestScore = curScore
-				//bestMove = move
-
-				if timeOver {
-					/*if debug {
-						fmt.Printf("Max%d: stopped execution. Out of time\n", newDepth)
-					}*/
-					return bestScore
-				}
-
-				// alpha-beta pruning
-				if bestScore > *parentsBestScore {
-					board.RetractMove(&move, takenPiece)
-					/*if debug {
-						fmt.Printf("MAX%d: AB Pruning because curScore %f is more than parents best score %f\n", newDepth, bestScore, *parentsBestScore)
-					}*/
-					return bestScore
-				}
-			}
-
-			board.RetractMove(&move, takenPiece)
-
-		case <-stopChan:
-			// Parent told us to stop execution.. must have been a bad child
-			/*if debug {
-				fmt.Printf("Max%d: stopped execution\n", newDepth)
-			}*/
-			return bestScore // Returning this score shouldn't do anything
-		}
+	if newDepth == 0 {
+		return board.quiesce(player, ply, alpha, beta, playerMoves, numParentMoves, control)
 	}
 
-	/*if debug {
-		fmt.Printf("MAX%d: Found bestscore %f moves left %d with move %s \n", newDepth, bestScore, len(playerMoves), bestMove.ToString())
-	}*/
-
-	return bestScore
-}
-func (board *Board) Min(player *Player, depth *int8, parentsBestScore *float32, parentStopChan <-chan struct{}, numParentMoves int) float32 {
-	playerMoves := board.LegalMovesForPlayer(*player)
-
-	if board.IsGameOverForPlayer(player, &playerMoves) {
-		return winMax
+	var staticScore float32
+	futile := control.canPruneFutile(newDepth)
+	nullMove := control.canNullMove(allowNull, depth) && !isDecisive(beta)
+	if futile || nullMove {
+		staticScore = control.staticScore(board, player, playerMoves, numParentMoves)
 	}
-
-	newDepth := *depth - 1
-
-	if newDepth == 0 {
-		return float32(len(playerMoves)*2) - float32(numParentMoves*2) + board.GetWeightedScoreForPlayer(player)
+	if nullMove && staticScore >= beta {
+		if score, ok := board.nullMove(player, depth, ply, beta, stopChan, len(playerMoves), numParentMoves, control); ok {
+			return score
+		}
 	}
 
-	//var bestMove Move
-	bestScore := bestMax
-	sort.Sort(playerMoves)
-
-	for _, move := range playerMoves {
-		takenPiece := *board.MakeMoveAndGetTakenPiece(&move)
-		curScore := board.Max(player.Opponent(), &newDepth, &bestScore, parentStopChan, len(playerMoves))
+	bestScore := bestMin
+	control.orderMoves(board, playerMoves, ply)
+	opponent := player.Opponent()
 
-		select {
-		default:
-			if curScore < bestScore {
-				bestScore = curScore
-				//bestMove = move
-
-				if timeOver {
-					/*if debug {
-						fmt.Printf("Max%d: stopped execution. Out of time\n", newDepth)
-					}*/
-					return bestScore
-				}
-
-				// alpha-beta pruning
-				if bestScore < *parentsBestScore {
-					board.RetractMove(&move, takenPiece)
-					/*if debug {
-						fmt.Printf("MAX%d: AB Pruning because curScore %f is less than parents best score %f\n", newDepth, bestScore, *parentsBestScore)
-					}*/
-					return bestScore
-				}
+	for i, move := range playerMoves {
+		// Futility pruning: a quiet move can't make up for being this far behind just before the leaves
+		if futile && i > 0 && board.PieceAt(&move.to) == EMPTY && staticScore+control.futilityMargin() <= alpha {
+			continue
+		}
 
+		takenPiece := control.makeMove(board, &move)
+		control.options.Trace.setMove(move)
+		childPV := Moves{}
+		var score float32
+		if i == 0 {
+			score = -board.Negamax(opponent, newDepth, ply+1, -beta, -alpha, stopChan, len(playerMoves), control, &childPV, true)
+		} else {
+			// Principal variation search: the first move is most likely the best, so the others only get a zero window
+			// that tells whether they beat alpha. Quiet moves late in the order are also searched a ply shallower
+			childDepth := newDepth
+			if control.canReduce(i, newDepth, takenPiece) {
+				childDepth--
 			}
+			score = -board.Negamax(opponent, childDepth, ply+1, zeroWindowAlpha(-alpha), -alpha, stopChan, len(playerMoves), control, &childPV, true)
+			if score > alpha && childDepth < newDepth {
+				childPV = Moves{}
+				score = -board.Negamax(opponent, newDepth, ply+1, zeroWindowAlpha(-alpha), -alpha, stopChan, len(playerMoves), control, &childPV, true)
+			}
+			// It beat alpha, so it needs an exact score
+			if score > alpha && score < beta {
+				childPV = Moves{}
+				score = -board.Negamax(opponent, newDepth, ply+1, -beta, -alpha, stopChan, len(playerMoves), control, &childPV, true)
+			}
+		}
+		control.retractMove(board, &move, takenPiece)
 
-			board.RetractMove(&move, takenPiece)
-
-		case <-parentStopChan:
+		select {
+		case <-stopChan:
 			// Parent told us to stop execution.. must have been a bad child
-			/*if debug {
-				fmt.Printf("Min%d: stopped execution\n", newDepth)
-			}*/
 			return bestScore // Returning this score shouldn't do anything
+		default:
 		}
 
+		if score > bestScore {
+			bestScore = score
+			*pv = append(Moves{move}, childPV...)
+		}
+		if control.isOver() {
+			return bestScore
+		}
+
+		// alpha-beta pruning
+		if bestScore >= beta {
+			control.recordCutoff(move, takenPiece, ply, depth)
+			return bestScore
+		}
+		if bestScore > alpha {
+			alpha = bestScore
+		}
 	}
 
-	/*if debug {
-		fmt.Printf("MIN%d: Found bestscore %f moves left %d with move %s \n", newDepth, bestScore, len(playerMoves), bestMove.ToString())
-	}*/
 	return bestScore
 }
 
@@ -26,6 +26,9 @@ func TestNewBoardFromString(t *testing.T) {
 
 var depth int8 = 8
 
+// The positions below always give the same move, however the goroutines would have been scheduled
+var deterministic = SearchOptions{Deterministic: true}
+
 func TestBoard_MinimaxMulti(t *testing.T) {
 	board := NewDefaultBoard()
 	player := Player(GOBOT)
@@ -49,7 +52,7 @@ func TestBoard_Minimax2(t *testing.T) {
 	buffer.WriteString("    A B C D E F")
 	board := NewBoardFromString(buffer.String())
 	player := Player(GOBOT)
-	move := board.MinimaxMulti(&player, &depth)
+	move := board.SearchWithOptions(&player, SearchLimits{Depth: depth}, deterministic, nil, nil)
 	moveExpected := Move{from: Location{2, 1}, to: Location{3, 0}}
 	if !move.Move().Equals(&moveExpected) {
 		t.Error("Move " + move.Move().ToString() + " should equal expected: " + moveExpected.ToString())
@@ -71,7 +74,7 @@ func TestBoard_Minimax3(t *testing.T) {
 	buffer.WriteString("    A B C D E F")
 	board := NewBoardFromString(buffer.String())
 	player := Player(GOBOT)
-	move := board.MinimaxMulti(&player, &depth)
+	move := board.SearchWithOptions(&player, SearchLimits{Depth: depth}, deterministic, nil, nil)
 	moveExpected := NewMoveFromString("D7D8")
 	if !move.Move().Equals(&moveExpected) {
 		t.Error("Move " + move.Move().ToString() + " should equal expected: " + moveExpected.ToString())
@@ -93,7 +96,7 @@ func TestBoard_Minimax4(t *testing.T) {
 	buffer.WriteString("    A B C D E F")
 	board := NewBoardFromString(buffer.String())
 	player := Player(GOBOT)
-	move := board.MinimaxMulti(&player, &depth)
+	move := board.SearchWithOptions(&player, SearchLimits{Depth: depth}, deterministic, nil, nil)
 	moveExpected := NewMoveFromString("D6E5")
 	if !move.move.Equals(&moveExpected) {
 		t.Error("Move " + move.move.ToString() + " should equal expected: " + moveExpected.ToString())
@@ -623,3 +626,20 @@ func TestBoard_FindMovesForKingAtLocation(t *testing.T) {
 		t.Error("Returned incorrect number of moves")
 	}
 }
+
+// Human's rook can take Gobot's bishop for free. At an odd depth the last move is the opponent's,
+// so the search only takes the bishop if those leaves are scored from the searching player's point of view
+func TestBoard_SearchTakesFreePiece(t *testing.T) {
+	SetDebug(false)
+	board, player, err := ParsePosition("K5/6/6/6/2B3/6/6/1kr3 h")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := NewMoveFromString("C1C4")
+	for _, depth := range []int8{1, 3} {
+		best := board.Search(&player, SearchLimits{Depth: depth}, nil, nil)
+		if !best.Move().Equals(&expected) {
+			t.Errorf("At depth %d the search should take the bishop, got %s", depth, best.Move().ToString())
+		}
+	}
+}
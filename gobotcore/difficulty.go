@@ -0,0 +1,103 @@
+package gobotcore
+
+import (
+	"errors"
+	"strings"
+)
+
+// A named playing strength. Weaker levels search less, pick among good moves at random and misjudge positions
+type Difficulty struct {
+	Name       string
+	Depth      int8  // Deepest iteration, 0 for no limit
+	Nodes      int64 // Nodes per move, 0 for no limit
+	Randomness float32
+	Mistakes   float32
+}
+
+var Difficulties = []Difficulty{
+	{Name: "beginner", Depth: 2, Randomness: 6, Mistakes: 4},
+	{Name: "easy", Depth: 3, Randomness: 3, Mistakes: 2},
+	{Name: "medium", Depth: 4, Nodes: 200000, Randomness: 1.5},
+	{Name: "hard", Depth: 6, Randomness: 0.5},
+	{Name: "max"},
+}
+
+// Bonuses that change what kind of positions the search goes for
+type Personality struct {
+	Name string
+	// Added for every capture the player could make next, minus the captures the opponent could make
+	CaptureBonus float32
+	// Added for every piece next to the player's king, minus the pieces next to the opponent's king
+	KingSafetyBonus float32
+}
+
+var Personalities = []Personality{
+	{Name: "balanced"},
+	{Name: "aggressive", CaptureBonus: 0.5},
+	{Name: "defensive", KingSafetyBonus: 0.75},
+}
+
+func ParseDifficulty(name string) (Difficulty, error) {
+	names := make([]string, len(Difficulties))
+	for i, difficulty := range Difficulties {
+		if strings.EqualFold(difficulty.Name, name) {
+			return difficulty, nil
+		}
+		names[i] = difficulty.Name
+	}
+	return Difficulty{}, errors.New("difficulty must be one of " + strings.Join(names, ", "))
+}
+
+func ParsePersonality(name string) (Personality, error) {
+	names := make([]string, len(Personalities))
+	for i, personality := range Personalities {
+		if strings.EqualFold(personality.Name, name) {
+			return personality, nil
+		}
+		names[i] = personality.Name
+	}
+	return Personality{}, errors.New("personality must be one of " + strings.Join(names, ", "))
+}
+
+// Sets the depth and node limits and the randomness of the level. A MoveTime in limits is kept
+func (difficulty Difficulty) Apply(limits *SearchLimits, options *SearchOptions) {
+	limits.Depth = difficulty.Depth
+	limits.Nodes = difficulty.Nodes
+	options.Randomness = difficulty.Randomness
+	options.Mistakes = difficulty.Mistakes
+}
+
+func (board *Board) countCaptures(player Player) int {
+	captures := 0
+	for _, move := range board.LegalMovesForPlayer(player) {
+		if board.PieceAt(&move.to).IsOwnedBy(player.Opponent()) {
+			captures++
+		}
+	}
+	return captures
+}
+
+// Counts the player's pieces on the squares around their king
+func (board *Board) countKingGuards(player *Player) int {
+	guards := 0
+	var row, col int8
+	for row = 0; row < boardRows; row++ {
+		for col = 0; col < boardCols; col++ {
+			king := Location{row: row, col: col}
+			piece := board.PieceAt(&king)
+			if !piece.IsKing() || !piece.IsOwnedBy(player) {
+				continue
+			}
+			var i, j int8
+			for i = -1; i <= 1; i++ {
+				for j = -1; j <= 1; j++ {
+					square := Location{row: row + i, col: col + j}
+					if (i != 0 || j != 0) && square.IsOnBoard() && board.PieceAt(&square).IsOwnedBy(player) {
+						guards++
+					}
+				}
+			}
+		}
+	}
+	return guards
+}
@@ -0,0 +1,93 @@
+package gobotcore
+
+import "testing"
+
+func TestParseDifficulty(t *testing.T) {
+	difficulty, err := ParseDifficulty("Easy")
+	if err != nil || difficulty.Name != "easy" {
+		t.Error("Names should be case insensitive")
+	}
+	if _, err := ParseDifficulty("impossible"); err == nil {
+		t.Error("Unknown difficulty should be rejected")
+	}
+	if _, err := ParsePersonality("aggressive"); err != nil {
+		t.Error(err)
+	}
+	if _, err := ParsePersonality("sneaky"); err == nil {
+		t.Error("Unknown personality should be rejected")
+	}
+}
+
+func TestDifficulty_Apply(t *testing.T) {
+	difficulty, _ := ParseDifficulty("medium")
+	limits := SearchLimits{MoveTime: moveTime}
+	var options SearchOptions
+	difficulty.Apply(&limits, &options)
+	if limits.Depth != 4 || limits.Nodes != 200000 || limits.MoveTime != moveTime || options.Randomness != 1.5 {
+		t.Error("Unexpected limits", limits, options)
+	}
+}
+
+func TestSearch_NodeLimit(t *testing.T) {
+	board := NewDefaultBoard()
+	gobot := Player(GOBOT)
+	var nodes int64
+	move := board.Search(&gobot, SearchLimits{Nodes: 2000}, nil, func(info SearchInfo) {
+		nodes = info.Nodes
+	})
+	if len(move.PV()) == 0 {
+		t.Error("Search should still return a move")
+	}
+	// Goroutines that are already running finish their node, so allow a little over
+	if nodes > 3000 {
+		t.Error("Search went far past the node limit:", nodes)
+	}
+}
+
+func TestSearch_Randomness(t *testing.T) {
+	board := NewDefaultBoard()
+	human := Player(HUMAN)
+	limits := SearchLimits{Depth: 2}
+	best := board.Search(&human, limits, nil, nil)
+
+	seen := map[Move]bool{}
+	for i := 0; i < 20; i++ {
+		move := board.SearchWithOptions(&human, limits, SearchOptions{Randomness: 100}, nil, nil)
+		if !board.IsValidHumanMove(move.Move()) {
+			t.Fatal("Random move must be legal: " + move.Move().ToString())
+		}
+		seen[*move.Move()] = true
+	}
+	if len(seen) < 2 {
+		t.Error("A large randomness should pick different moves, always picked " + best.Move().ToString())
+	}
+
+	move := board.SearchWithOptions(&human, limits, SearchOptions{Randomness: 0.001}, nil, nil)
+	if *move.Score() != *best.Score() {
+		t.Error("A tiny randomness should only pick moves as good as the best")
+	}
+}
+
+func TestBoard_PersonalityTerms(t *testing.T) {
+	board, _, err := ParsePosition("1K4/NBR3/6/6/2r3/6/6/4k1 g")
+	if err != nil {
+		t.Fatal(err)
+	}
+	gobot := Player(GOBOT)
+	human := Player(HUMAN)
+	if guards := board.countKingGuards(&gobot); guards != 3 {
+		t.Error("Gobot's king has 3 pieces next to it, counted", guards)
+	}
+	if guards := board.countKingGuards(&human); guards != 0 {
+		t.Error("Human's king is alone, counted", guards)
+	}
+	// The rooks on C7 and C4 can take each other
+	if board.countCaptures(gobot) != 1 || board.countCaptures(human) != 1 {
+		t.Error("Each side should have one capture")
+	}
+
+	control := &searchControl{options: SearchOptions{Personality: Personality{KingSafetyBonus: 1}}}
+	if score := control.evaluate(&board, &gobot) - board.GetWeightedScoreForPlayer(&gobot); score != 3 {
+		t.Error("Defensive bonus should be 3, is", score)
+	}
+}
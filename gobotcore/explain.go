@@ -0,0 +1,100 @@
+package gobotcore
+
+import (
+	"fmt"
+	"io"
+)
+
+/* Explain breaks the static score of the search into its terms, for debugging changes to the evaluation.
+ * Every term is worked out for each player on its own, and a player's score is the sum of their terms minus the opponent's.
+ * The search counts the opponent's mobility in the position before the last move, since it already has those moves.
+ * Here both players' moves are counted in the same position, so the total can differ from a search's leaf score
+ */
+type Explanation struct {
+	Terms []EvalTerm
+}
+
+// A term of the evaluation. Gobot and Human are what it adds to each player's side of the score
+type EvalTerm struct {
+	Name  string
+	Gobot float32
+	Human float32
+}
+
+// Each player gets this many points for every legal move they have
+const mobilityWeight float32 = 2
+
+// The order the material terms are listed in
+var explainedPieces = []Piece{PAWN_GOB, BISHOP_GOB, KNIGHT_GOB, ROOK_GOB, KING_GOB}
+
+func (board *Board) Explain(personality Personality) Explanation {
+	gobot, human := Player(GOBOT), Player(HUMAN)
+	explanation := Explanation{}
+	for _, piece := range explainedPieces {
+		term := EvalTerm{Name: "material " + piece.LongName()}
+		var row, col int8
+		for row = 0; row < boardRows; row++ {
+			for col = 0; col < boardCols; col++ {
+				location := Location{row: row, col: col}
+				found := board.PieceAt(&location)
+				if found.LongName() != piece.LongName() {
+					continue
+				}
+				if found.IsOwnedBy(&gobot) {
+					term.Gobot += found.Weight()
+				} else {
+					term.Human += found.Weight()
+				}
+			}
+		}
+		explanation.Terms = append(explanation.Terms, term)
+	}
+
+	explanation.Terms = append(explanation.Terms, EvalTerm{
+		Name:  "mobility",
+		Gobot: mobilityWeight * float32(len(board.LegalMovesForPlayer(gobot))),
+		Human: mobilityWeight * float32(len(board.LegalMovesForPlayer(human))),
+	})
+	explanation.Terms = append(explanation.Terms, EvalTerm{
+		Name:  "captures",
+		Gobot: personality.CaptureBonus * float32(board.countCaptures(gobot)),
+		Human: personality.CaptureBonus * float32(board.countCaptures(human)),
+	})
+	explanation.Terms = append(explanation.Terms, EvalTerm{
+		Name:  "king safety",
+		Gobot: personality.KingSafetyBonus * float32(board.countKingGuards(&gobot)),
+		Human: personality.KingSafetyBonus * float32(board.countKingGuards(&human)),
+	})
+	return explanation
+}
+
+// What the term adds to player's score
+func (term EvalTerm) ScoreFor(player Player) float32 {
+	if player == GOBOT {
+		return term.Gobot - term.Human
+	}
+	return term.Human - term.Gobot
+}
+
+// The total score from player's point of view
+func (explanation Explanation) ScoreFor(player Player) float32 {
+	var score float32
+	for _, term := range explanation.Terms {
+		score += term.ScoreFor(player)
+	}
+	return score
+}
+
+// Write prints a table with a row for every term and the total, and the score from each player's point of view
+func (explanation Explanation) Write(w io.Writer) error {
+	var gobotTotal, humanTotal float32
+	lines := fmt.Sprintf("%-18s %9s %9s %10s %10s\n", "term", "Gobot", "Human", "for Gobot", "for Human")
+	for _, term := range explanation.Terms {
+		gobotTotal += term.Gobot
+		humanTotal += term.Human
+		lines += fmt.Sprintf("%-18s %9.2f %9.2f %10.2f %10.2f\n", term.Name, term.Gobot, term.Human, term.ScoreFor(GOBOT), term.ScoreFor(HUMAN))
+	}
+	lines += fmt.Sprintf("%-18s %9.2f %9.2f %10.2f %10.2f\n", "total", gobotTotal, humanTotal, explanation.ScoreFor(GOBOT), explanation.ScoreFor(HUMAN))
+	_, err := io.WriteString(w, lines)
+	return err
+}
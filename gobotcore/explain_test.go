@@ -0,0 +1,66 @@
+package gobotcore
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestExplain_Symmetric(t *testing.T) {
+	personality := Personality{CaptureBonus: 0.5, KingSafetyBonus: 0.75}
+	for _, position := range searchPositions {
+		board, _, err := ParsePosition(position)
+		if err != nil {
+			t.Fatal(err)
+		}
+		swapped := swapSides(&board)
+		explanation := board.Explain(personality)
+		swappedExplanation := swapped.Explain(personality)
+		for i, term := range explanation.Terms {
+			swappedTerm := swappedExplanation.Terms[i]
+			if term.Gobot != swappedTerm.Human || term.Human != swappedTerm.Gobot {
+				t.Errorf("%s: %s should swap with the sides, got %+v and %+v", position, term.Name, term, swappedTerm)
+			}
+		}
+		if explanation.ScoreFor(GOBOT) != -explanation.ScoreFor(HUMAN) {
+			t.Errorf("%s: the players' scores should add up to 0", position)
+		}
+	}
+
+	start := NewDefaultBoard()
+	if score := start.Explain(personality).ScoreFor(GOBOT); score != 0 {
+		t.Errorf("The start position should be even, got %v", score)
+	}
+}
+
+func TestExplain_MatchesStaticScore(t *testing.T) {
+	personality := Personality{CaptureBonus: 0.5, KingSafetyBonus: 0.75}
+	control := newSearchControl(nil, SearchLimits{}, SearchOptions{Personality: personality}, nil)
+	defer control.finish()
+	for _, position := range searchPositions {
+		board, player, err := ParsePosition(position)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// Explain counts the opponent's moves in the same position, so the search has to as well
+		moves := board.LegalMovesForPlayer(player)
+		opponentMoves := board.LegalMovesForPlayer(*player.Opponent())
+		expected := control.staticScore(&board, &player, moves, len(opponentMoves))
+		if score := board.Explain(personality).ScoreFor(player); score != expected {
+			t.Errorf("%s: Explain should score %v like the search, got %v", position, expected, score)
+		}
+	}
+}
+
+func TestExplanation_Write(t *testing.T) {
+	board := NewDefaultBoard()
+	var out bytes.Buffer
+	if err := board.Explain(Personality{}).Write(&out); err != nil {
+		t.Fatal(err)
+	}
+	for _, line := range []string{"material Bishop", "material King", "mobility", "king safety", "total"} {
+		if !strings.Contains(out.String(), line) {
+			t.Errorf("The table should have a %s row, got\n%s", line, out.String())
+		}
+	}
+}
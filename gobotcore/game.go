@@ -0,0 +1,121 @@
+package gobotcore
+
+import "errors"
+
+var (
+	ErrIllegalMove = errors.New("illegal move")
+	ErrNoHistory   = errors.New("no moves to take back")
+	ErrGameOver    = errors.New("game is over")
+)
+
+// A move that has been played in a game, with what it took so that it can be taken back
+type PlayedMove struct {
+	Move       Move
+	Player     Player
+	Piece      Piece // The piece that moved, before it morphed
+	TakenPiece Piece
+}
+
+// Game keeps track of the board, whose turn it is and every move made so far
+type Game struct {
+	start     Board
+	startTurn Player
+	board     Board
+	turn      Player
+	history   []PlayedMove
+}
+
+func NewGame(firstPlayer Player) *Game {
+	return NewGameFromBoard(NewDefaultBoard(), firstPlayer)
+}
+
+func NewGameFromBoard(board Board, toMove Player) *Game {
+	return &Game{start: board, startTurn: toMove, board: board, turn: toMove}
+}
+
+func NewGameFromPosition(position string) (*Game, error) {
+	board, toMove, err := ParsePosition(position)
+	if err != nil {
+		return nil, err
+	}
+	return NewGameFromBoard(board, toMove), nil
+}
+
+// Returns a copy of the current board
+func (game *Game) Board() Board {
+	return game.board
+}
+
+// Returns the board and side to move the game started from
+func (game *Game) Start() (Board, Player) {
+	return game.start, game.startTurn
+}
+
+func (game *Game) Turn() Player {
+	return game.turn
+}
+
+func (game *Game) History() []PlayedMove {
+	return append([]PlayedMove(nil), game.history...)
+}
+
+func (game *Game) Position() string {
+	return game.board.Position(game.turn)
+}
+
+func (game *Game) LegalMoves() Moves {
+	return game.board.LegalMovesForPlayer(game.turn)
+}
+
+// Plays move for the side to move if it is legal
+func (game *Game) MakeMove(move Move) error {
+	if game.IsOver() {
+		return ErrGameOver
+	}
+	if !game.board.IsValidMoveForPlayer(&move, game.turn) {
+		return ErrIllegalMove
+	}
+	piece := game.board.PieceAt(&move.from)
+	takenPiece := *game.board.MakeMoveAndGetTakenPiece(&move)
+	game.history = append(game.history, PlayedMove{Move: move, Player: game.turn, Piece: piece, TakenPiece: takenPiece})
+	game.turn = *game.turn.Opponent()
+	return nil
+}
+
+// Takes back the last move played
+func (game *Game) Undo() (PlayedMove, error) {
+	if len(game.history) == 0 {
+		return PlayedMove{}, ErrNoHistory
+	}
+	last := game.history[len(game.history)-1]
+	game.history = game.history[:len(game.history)-1]
+	game.board.RetractMove(&last.Move, last.TakenPiece)
+	game.turn = last.Player
+	return last, nil
+}
+
+// Puts the game back to how it started
+func (game *Game) Reset() {
+	game.board = game.start
+	game.turn = game.startTurn
+	game.history = nil
+}
+
+func (game *Game) IsOver() bool {
+	_, over := game.Winner()
+	return over
+}
+
+// Returns the winner and true if the game is over. A player loses when their king is taken or they can't move
+func (game *Game) Winner() (Player, bool) {
+	for _, player := range []Player{GOBOT, HUMAN} {
+		if game.board.isKingDeadForPlayer(&player) {
+			return *player.Opponent(), true
+		}
+	}
+	moves := game.LegalMoves()
+	if len(moves) == 0 {
+		return *game.turn.Opponent(), true
+	}
+	return game.turn, false
+}
@@ -0,0 +1,128 @@
+package gobotcore
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestGame_MakeMoveAndUndo(t *testing.T) {
+	game := NewGame(HUMAN)
+	if err := game.MakeMove(NewMoveFromString("C3C5")); err != ErrIllegalMove {
+		t.Error("Pawns can only move one square")
+	}
+	if err := game.MakeMove(NewMoveFromString("C3C4")); err != nil {
+		t.Error("Move is valid")
+	}
+	if game.Turn() != GOBOT {
+		t.Error("Should be Gobot's turn")
+	}
+	if err := game.MakeMove(NewMoveFromString("C3C4")); err != ErrIllegalMove {
+		t.Error("Gobot cannot move Human pieces")
+	}
+
+	if _, err := game.Undo(); err != nil {
+		t.Error("Should be able to undo")
+	}
+	if game.Board() != NewDefaultBoard() || game.Turn() != HUMAN {
+		t.Error("Undo should restore the board and turn")
+	}
+	if _, err := game.Undo(); err != ErrNoHistory {
+		t.Error("Nothing left to undo")
+	}
+}
+
+func TestGame_Winner(t *testing.T) {
+	game, err := NewGameFromPosition("K5/6/6/6/6/6/2B3/1r1k2 g")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, over := game.Winner(); over {
+		t.Error("Game is not over yet")
+	}
+	game.MakeMove(NewMoveFromString("C2D1"))
+	winner, over := game.Winner()
+	if !over || winner != GOBOT {
+		t.Error("Gobot took the king and should win")
+	}
+	if game.MakeMove(NewMoveFromString("B1B2")) != ErrGameOver {
+		t.Error("No moves after the game is over")
+	}
+}
+
+func TestParsePosition(t *testing.T) {
+	board, toMove, err := ParsePosition(StartPosition)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if board != NewDefaultBoard() || toMove != HUMAN {
+		t.Error("Start position should match default board")
+	}
+	if board.Position(toMove) != StartPosition {
+		t.Error("Position should round trip, got " + board.Position(toMove))
+	}
+
+	for _, bad := range []string{"", "6/6 h", "1K4/NBRRBN/2PP2/6/6/2pp2/nbrrbn/4k1 x", "7/6/6/6/6/6/6/6 h", "1X4/6/6/6/6/6/6/6 h"} {
+		if _, _, err := ParsePosition(bad); err == nil {
+			t.Error("Position should be rejected: " + bad)
+		}
+	}
+}
+
+func TestParsePosition_LongRows(t *testing.T) {
+	rest := "/6/6/6/6/6/6/4k1 h"
+	tests := []struct {
+		name     string
+		position string
+	}{
+		{"too many empty squares", "99" + rest},
+		{"empty squares past the row, then a piece", "9K" + rest},
+		{"enough empty squares to wrap an int8", strings.Repeat("9", 15) + "K" + rest},
+		{"enough empty squares to wrap an int8 back to 0", strings.Repeat("8", 32) + "K5" + rest},
+	}
+	for _, test := range tests {
+		if _, _, err := ParsePosition(test.position); err == nil {
+			t.Error("Position should be rejected: " + test.name)
+		}
+	}
+}
+
+func TestParseMove(t *testing.T) {
+	move, err := ParseMove("c3c4")
+	expected := NewMoveFromString("C3C4")
+	if err != nil || !move.Equals(&expected) {
+		t.Error("Should parse lowercase moves")
+	}
+	for _, bad := range []string{"", "C3", "C3C9", "G1A1", "C3C4C5"} {
+		if _, err := ParseMove(bad); err == nil {
+			t.Error("Move should be rejected: " + bad)
+		}
+	}
+}
+
+func TestGame_Record(t *testing.T) {
+	game := NewGame(GOBOT)
+	game.MakeMove(NewMoveFromString("C6C5"))
+	game.MakeMove(NewMoveFromString("C3C4"))
+
+	var buffer bytes.Buffer
+	if err := game.WriteRecord(&buffer); err != nil {
+		t.Fatal(err)
+	}
+	start := NewDefaultBoard()
+	if buffer.String() != "start "+start.Position(GOBOT)+"\nC6C5\nC3C4\n" {
+		t.Error("Unexpected record: " + buffer.String())
+	}
+
+	loaded, err := ReadGameRecord(&buffer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded.Position() != game.Position() || len(loaded.History()) != 2 {
+		t.Error("Loaded game should match the saved game")
+	}
+
+	if _, err := ReadGameRecord(strings.NewReader("start " + StartPosition + "\nC3C5\n")); err == nil {
+		t.Error("Records with illegal moves should be rejected")
+	}
+}
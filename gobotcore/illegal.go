@@ -0,0 +1,93 @@
+package gobotcore
+
+import "strings"
+
+// Explains why player can't make move, in a sentence that can be shown to a person. Returns "" if the move is legal
+func (board *Board) WhyIllegal(move *Move, player Player) string {
+	if !move.from.IsOnBoard() || !move.to.IsOnBoard() {
+		return "That square is not on the board."
+	}
+	if board.IsValidMoveForPlayer(move, player) {
+		return ""
+	}
+
+	piece := board.PieceAt(&move.from)
+	target := board.PieceAt(&move.to)
+	switch {
+	case piece.IsEmpty():
+		return "There is no piece on " + move.from.ToString() + "."
+	case !piece.IsOwnedBy(&player):
+		return "The piece on " + move.from.ToString() + " belongs to " + player.Opponent().Name() + "."
+	case move.from.Equals(&move.to):
+		return "The piece has to move somewhere."
+	case target.IsOwnedBy(&player):
+		return "You can't capture your own piece on " + move.to.ToString() + "."
+	}
+
+	cols := move.to.col - move.from.col
+	rows := move.to.row - move.from.row
+	forward := rows > 0
+	if player == GOBOT {
+		forward = rows < 0
+	}
+	capturing := target.IsOwnedBy(player.Opponent())
+	name := strings.ToLower(piece.LongName())
+
+	switch piece {
+	case PAWN_GOB, PAWN_HUM:
+		if !forward || abs(rows) != 1 || abs(cols) > 1 {
+			return "A pawn only moves one square forward."
+		}
+		if cols == 0 {
+			return "A pawn can't capture straight ahead, only diagonally."
+		}
+		return "A pawn can only move diagonally to capture."
+	case KING_GOB, KING_HUM:
+		if rows != 0 || abs(cols) != 1 {
+			return "The king only moves one square sideways."
+		}
+		return "The king can only move toward column " + kingSide(player) + " unless it is capturing."
+	case KNIGHT_GOB, KNIGHT_HUM:
+		if !(abs(cols) == 1 && abs(rows) == 2) && !(abs(cols) == 2 && abs(rows) == 1) {
+			return "A knight moves in an L shape."
+		}
+	case BISHOP_GOB, BISHOP_HUM:
+		if abs(cols) != abs(rows) {
+			return "A bishop moves diagonally."
+		}
+		return board.whySlideIllegal(move, name, forward, capturing)
+	case ROOK_GOB, ROOK_HUM:
+		if cols != 0 && rows != 0 {
+			return "A rook moves in straight lines."
+		}
+		return board.whySlideIllegal(move, name, forward, capturing)
+	}
+
+	if !forward && !capturing {
+		return "A " + name + " can only move backward to capture."
+	}
+	return "That move is not allowed."
+}
+
+func (board *Board) whySlideIllegal(move *Move, name string, forward bool, capturing bool) string {
+	isBackward := !forward && move.to.row != move.from.row
+	if isBackward && !capturing {
+		return "A " + name + " can only move backward to capture."
+	}
+	return "The path to " + move.to.ToString() + " is blocked."
+}
+
+// The column the king heads toward when it moves into an empty square
+func kingSide(player Player) string {
+	if player == GOBOT {
+		return "F"
+	}
+	return "A"
+}
+
+func abs(value int8) int8 {
+	if value < 0 {
+		return -value
+	}
+	return value
+}
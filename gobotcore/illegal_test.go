@@ -0,0 +1,40 @@
+package gobotcore
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestBoard_WhyIllegal(t *testing.T) {
+	var buffer bytes.Buffer
+	buffer.WriteString("8   - K - - - -\n")
+	buffer.WriteString("7   N B R R B N\n")
+	buffer.WriteString("6   - - P P - -\n")
+	buffer.WriteString("5   - - - - - -\n")
+	buffer.WriteString("4   - - - - - -\n")
+	buffer.WriteString("3   - - p p - -\n")
+	buffer.WriteString("2   n b r r b n\n")
+	buffer.WriteString("1   - - - - k -\n")
+	buffer.WriteString("\n")
+	buffer.WriteString("    A B C D E F")
+	board := NewBoardFromString(buffer.String())
+
+	tests := map[string]string{
+		"C3C4": "",
+		"A4A5": "There is no piece on A4.",
+		"C7C6": "The piece on C7 belongs to Gobot.",
+		"C2D2": "You can't capture your own piece on D2.",
+		"C3C5": "A pawn only moves one square forward.",
+		"C3B4": "A pawn can only move diagonally to capture.",
+		"E1F1": "The king can only move toward column A unless it is capturing.",
+		"C2C5": "The path to C5 is blocked.",
+		"B2C4": "A bishop moves diagonally.",
+		"A2C4": "A knight moves in an L shape.",
+	}
+	for moveString, expected := range tests {
+		move := NewMoveFromString(moveString)
+		if reason := board.WhyIllegal(&move, HUMAN); reason != expected {
+			t.Error(moveString + ": expected \"" + expected + "\" got \"" + reason + "\"")
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package gobotcore
 
 import (
+	"errors"
 	"strconv"
 	"strings"
 )
@@ -15,8 +16,8 @@ type PieceLocation struct {
 	location Location
 }
 
-var alphabet string = "ABCDEF"
-var alphabetReversed string = "FEDCBA"
+// Column letters, as many as the widest board can use
+var alphabet string = "ABCDEFGH"
 
 func NewLocation(col, row int8) Location {
 	location := Location{col: col, row: row}
@@ -45,10 +46,32 @@ func NewLocationFromString(readable string) Location {
 	}
 }
 
+// Same as NewLocationFromString but returns an error instead of panicking or returning a bad location
+func ParseLocation(readable string) (Location, error) {
+	if len(readable) != 2 {
+		return Location{}, errors.New("location must be 2 characters long, like C2")
+	}
+	col := int8(strings.Index(alphabet, strings.ToUpper(readable[:1])))
+	row := AtoiEZPZ(readable[1:]) - 1
+	location := Location{col: col, row: row}
+	if col < 0 || !location.IsOnBoard() {
+		return Location{}, errors.New("location " + readable + " is not on the board")
+	}
+	return location, nil
+}
+
 func NewLocationsFromString(fullReadable string) (Location, Location) {
 	return NewLocationFromString(fullReadable[:2]), NewLocationFromString(fullReadable[2:])
 }
 
+func (location Location) Col() int8 {
+	return location.col
+}
+
+func (location Location) Row() int8 {
+	return location.row
+}
+
 func (location Location) ToString() string {
 	return string(alphabet[location.col]) + strconv.Itoa(int(location.row+1))
 }
@@ -58,7 +81,7 @@ func ToStringMultipleLocations(source Location, destination Location) string {
 }
 
 func ToStringMultipleLocationsFlipped(source Location, destination Location) string {
-	return string(alphabetReversed[source.col]) + strconv.Itoa(int(boardRows-source.row)) + string(alphabetReversed[destination.col]) + strconv.Itoa(int(boardRows-destination.row))
+	return string(alphabet[boardCols-1-source.col]) + strconv.Itoa(int(boardRows-source.row)) + string(alphabet[boardCols-1-destination.col]) + strconv.Itoa(int(boardRows-destination.row))
 }
 
 func (location *Location) IsOnBoard() bool {
@@ -1,5 +1,10 @@
 package gobotcore
 
+import (
+	"errors"
+	"strings"
+)
+
 type Move struct {
 	from   Location
 	to     Location
@@ -9,6 +14,7 @@ type Move struct {
 type ScoredMove struct {
 	move  Move
 	score float32
+	pv    Moves // Principal variation, starting with move
 }
 
 type Moves []Move
@@ -21,6 +27,23 @@ func NewMoveFromString(str string) Move {
 	return NewMove(NewLocationsFromString(str))
 }
 
+// Parses a move like "C2C3" without panicking on bad input
+func ParseMove(str string) (Move, error) {
+	str = strings.TrimSpace(str)
+	if len(str) != 4 {
+		return Move{}, errors.New("move must be 4 characters long, like C2C3")
+	}
+	from, errFrom := ParseLocation(str[:2])
+	to, errTo := ParseLocation(str[2:])
+	if errFrom != nil {
+		return Move{}, errFrom
+	}
+	if errTo != nil {
+		return Move{}, errTo
+	}
+	return NewMove(from, to), nil
+}
+
 func (move Move) ToString() string {
 	return ToStringMultipleLocations(move.from, move.to)
 }
@@ -65,6 +88,18 @@ func (move ScoredMove) Score() *float32 {
 	return &move.score
 }
 
+func (move ScoredMove) PV() Moves {
+	return move.pv
+}
+
+func (moves Moves) ToString() string {
+	strs := make([]string, len(moves))
+	for i, move := range moves {
+		strs[i] = move.ToString()
+	}
+	return strings.Join(strs, " ")
+}
+
 // Implementing the sort interface
 func (move Moves) Len() int {
 	return len(move)
@@ -0,0 +1,284 @@
+package gobotcore
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/rand"
+	"os"
+	"strings"
+)
+
+/* A small neural network that can score positions instead of the hand written evaluation. Pass it in SearchOptions.Network.
+ * The input has one feature for every kind of piece on every square, set when that piece stands there.
+ * Two hidden layers with clipped ReLU, between 0 and 1, lead to a single output: Gobot's chance to win as a logit,
+ * which times networkScoreScale is the score from Gobot's point of view.
+ *
+ * The first layer is the biggest, but only a few of its inputs are set, and a move only changes two or three of them.
+ * So the search keeps its sums in an accumulator that moves update, see accumulator.go, in int16 scaled by firstLayerScale.
+ * The other layers are small enough to work out in float32 every time.
+ * Training, see training.go, works in float32 throughout, and the int16 weights are rounded from those.
+ */
+type Network struct {
+	hidden1 int
+	hidden2 int
+
+	// weights1[feature*hidden1+i] connects an input feature to hidden neuron i of the first layer
+	weights1 []float32
+	biases1  []float32
+	// weights2[i*hidden2+j] connects neuron i of the first layer to neuron j of the second
+	weights2 []float32
+	biases2  []float32
+	weights3 []float32
+	biases3  []float32 // Just the one, for the output
+
+	// The first layer rounded for the accumulator
+	quantWeights1 []int16
+	quantBiases1  []int16
+}
+
+const (
+	// Gobot's and Human's kinds of pieces apart
+	numPieceKinds = int(KING_HUM)
+
+	// The int16 first layer holds the float weights times this
+	firstLayerScale = 256
+
+	// Biggest first layer weight or bias. A full board adds up a weight for every square and the bias,
+	// which has to fit in the int16 sums of the accumulator
+	maxFirstLayerWeight float32 = float32(math.MaxInt16/(int(maxBoardCols)*int(maxBoardRows)+1)) / firstLayerScale
+
+	// A win chance of sigmoid(score / networkScoreScale), so a won position is worth about as much as winning a few pieces,
+	// and a king capture doesn't swamp everything else when training
+	networkScoreScale float32 = 20
+
+	// Biggest score the network may give, so that it can never look like a win or loss
+	maxNetworkScore float32 = 10000
+
+	networkMagic   = "GBNN"
+	networkVersion = 1
+)
+
+// A network with random weights, to start training from
+func NewNetwork(hidden1 int, hidden2 int, seed int64) *Network {
+	random := rand.New(rand.NewSource(seed))
+	network := newZeroNetwork(hidden1, hidden2)
+	// Small enough that a full board doesn't push every neuron of the first layer past the clip
+	randomize(network.weights1, random, 0.1)
+	randomize(network.weights2, random, 1/float32(math.Sqrt(float64(hidden1))))
+	randomize(network.weights3, random, 1/float32(math.Sqrt(float64(hidden2))))
+	for i := range network.biases1 {
+		network.biases1[i] = 0.5
+	}
+	network.quantize()
+	return network
+}
+
+func newZeroNetwork(hidden1 int, hidden2 int) *Network {
+	return &Network{
+		hidden1:  hidden1,
+		hidden2:  hidden2,
+		weights1: make([]float32, numFeatures()*hidden1),
+		biases1:  make([]float32, hidden1),
+		weights2: make([]float32, hidden1*hidden2),
+		biases2:  make([]float32, hidden2),
+		weights3: make([]float32, hidden2),
+		biases3:  make([]float32, 1),
+	}
+}
+
+func randomize(weights []float32, random *rand.Rand, size float32) {
+	for i := range weights {
+		weights[i] = (random.Float32()*2 - 1) * size
+	}
+}
+
+// Every kind of piece on every square of the variant's board
+func numFeatures() int {
+	return numPieceKinds * int(boardRows) * int(boardCols)
+}
+
+// The input feature set by piece standing at location
+func featureIndex(piece Piece, location Location) int {
+	return (int(piece)-1)*int(boardRows)*int(boardCols) + int(location.row)*int(boardCols) + int(location.col)
+}
+
+// Rounds the first layer for the accumulator. Called whenever the float weights change.
+// Weights past maxFirstLayerWeight, say from a file, are cut down first so the float and int16 layers agree
+func (network *Network) quantize() {
+	network.quantWeights1 = make([]int16, len(network.weights1))
+	for i, weight := range network.weights1 {
+		network.weights1[i] = clampFirstLayer(weight)
+		network.quantWeights1[i] = quantizeWeight(network.weights1[i])
+	}
+	network.quantBiases1 = make([]int16, len(network.biases1))
+	for i, bias := range network.biases1 {
+		network.biases1[i] = clampFirstLayer(bias)
+		network.quantBiases1[i] = quantizeWeight(network.biases1[i])
+	}
+}
+
+func quantizeWeight(weight float32) int16 {
+	return int16(math.Round(float64(weight) * firstLayerScale))
+}
+
+// Keeps a first layer weight within maxFirstLayerWeight, so the accumulator can't overflow
+func clampFirstLayer(weight float32) float32 {
+	return float32(math.Max(-float64(maxFirstLayerWeight), math.Min(float64(maxFirstLayerWeight), float64(weight))))
+}
+
+func clippedReLU(x float32) float32 {
+	if x < 0 {
+		return 0
+	}
+	if x > 1 {
+		return 1
+	}
+	return x
+}
+
+// Evaluate scores the board from Gobot's point of view in float32, the way training sees the network.
+// The search uses the int16 accumulator instead, which can differ by the rounding of the first layer
+func (network *Network) Evaluate(board *Board) float32 {
+	return networkScore(network.output(network.activations(board)))
+}
+
+// The outputs of the first layer in float32
+func (network *Network) activations(board *Board) []float32 {
+	sums := network.firstLayer(board)
+	for i := range sums {
+		sums[i] = clippedReLU(sums[i])
+	}
+	return sums
+}
+
+// The sums of the first layer before the activation
+func (network *Network) firstLayer(board *Board) []float32 {
+	sums := append([]float32(nil), network.biases1...)
+	var row, col int8
+	for row = 0; row < boardRows; row++ {
+		for col = 0; col < boardCols; col++ {
+			location := Location{row: row, col: col}
+			piece := board.PieceAt(&location)
+			if piece.IsEmpty() {
+				continue
+			}
+			weights := network.weights1[featureIndex(piece, location)*network.hidden1:]
+			for i := range sums {
+				sums[i] += weights[i]
+			}
+		}
+	}
+	return sums
+}
+
+// The second layer and the output logit, from the activations of the first layer
+func (network *Network) output(activations []float32) float32 {
+	score := network.biases3[0]
+	for j := 0; j < network.hidden2; j++ {
+		sum := network.biases2[j]
+		for i, activation := range activations {
+			sum += activation * network.weights2[i*network.hidden2+j]
+		}
+		score += clippedReLU(sum) * network.weights3[j]
+	}
+	return score
+}
+
+// The score of an output logit
+func networkScore(logit float32) float32 {
+	score := logit * networkScoreScale
+	if score > maxNetworkScore {
+		return maxNetworkScore
+	}
+	if score < -maxNetworkScore {
+		return -maxNetworkScore
+	}
+	return score
+}
+
+/* The weights file is little endian: "GBNN", then the version, the number of input features and the sizes
+ * of the two hidden layers as uint32. Then the float32 weights and biases of every layer, first layer first,
+ * each weight matrix in the order of the slices above
+ */
+func (network *Network) Write(w io.Writer) error {
+	writer := bufio.NewWriter(w)
+	writer.WriteString(networkMagic)
+	header := []uint32{networkVersion, uint32(numFeatures()), uint32(network.hidden1), uint32(network.hidden2)}
+	if err := binary.Write(writer, binary.LittleEndian, header); err != nil {
+		return err
+	}
+	for _, values := range network.parameters() {
+		if err := binary.Write(writer, binary.LittleEndian, values); err != nil {
+			return err
+		}
+	}
+	return writer.Flush()
+}
+
+func ReadNetwork(r io.Reader) (*Network, error) {
+	reader := bufio.NewReader(r)
+	magic := make([]byte, len(networkMagic))
+	if _, err := io.ReadFull(reader, magic); err != nil || string(magic) != networkMagic {
+		return nil, errors.New("not a network weights file")
+	}
+	header := make([]uint32, 4)
+	if err := binary.Read(reader, binary.LittleEndian, header); err != nil {
+		return nil, err
+	}
+	if header[0] != networkVersion {
+		return nil, fmt.Errorf("weights file version %d isn't supported", header[0])
+	}
+	if header[1] != uint32(numFeatures()) {
+		return nil, fmt.Errorf("weights file has %d inputs, the board of the variant needs %d", header[1], numFeatures())
+	}
+	if header[2] == 0 || header[2] > 4096 || header[3] == 0 || header[3] > 4096 {
+		return nil, fmt.Errorf("weights file has hidden layers of %d and %d", header[2], header[3])
+	}
+
+	network := newZeroNetwork(int(header[2]), int(header[3]))
+	for _, values := range network.parameters() {
+		if err := binary.Read(reader, binary.LittleEndian, values); err != nil {
+			return nil, errors.New("weights file is cut short")
+		}
+	}
+	network.quantize()
+	return network, nil
+}
+
+func (network *Network) Save(path string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	err = network.Write(file)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+func LoadNetwork(path string) (*Network, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return ReadNetwork(file)
+}
+
+// The network an evaluator option names: "classic" for the hand written evaluation, which is nil, or the path of a weights file
+func ParseEvaluator(value string) (*Network, error) {
+	if value == "" || strings.EqualFold(value, "classic") {
+		return nil, nil
+	}
+	return LoadNetwork(value)
+}
+
+// Every weight and bias, in the order of the weights file
+func (network *Network) parameters() [][]float32 {
+	return [][]float32{network.weights1, network.biases1, network.weights2, network.biases2, network.weights3, network.biases3}
+}
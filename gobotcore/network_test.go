@@ -0,0 +1,183 @@
+package gobotcore
+
+import (
+	"bytes"
+	"math"
+	"testing"
+)
+
+func TestNetwork_AccumulatorFollowsMoves(t *testing.T) {
+	network := NewNetwork(16, 8, 1)
+	control := newSearchControl(nil, SearchLimits{}, SearchOptions{Network: network}, nil)
+	defer control.finish()
+	board := NewDefaultBoard()
+	start := board
+	player := Player(HUMAN)
+
+	type played struct {
+		move  Move
+		taken Piece
+	}
+	var moves []played
+	// Always the first legal move, which gets to captures and morphs soon enough
+	for i := 0; i < 12; i++ {
+		legal := board.LegalMovesForPlayer(player)
+		if board.IsGameOverForPlayer(&player, &legal) {
+			break
+		}
+		move := legal[0]
+		moves = append(moves, played{move: move, taken: control.makeMove(&board, &move)})
+		player = *player.Opponent()
+
+		fresh := network.newAccumulator(&board)
+		for j, sum := range control.accumulator(&board).sums {
+			if sum != fresh.sums[j] {
+				t.Fatalf("After %d moves the accumulator is off from a fresh one", i+1)
+			}
+		}
+	}
+	for i := len(moves) - 1; i >= 0; i-- {
+		control.retractMove(&board, &moves[i].move, moves[i].taken)
+	}
+	if board != start {
+		t.Fatal("Taking the moves back should give the start position")
+	}
+	fresh := network.newAccumulator(&board)
+	for j, sum := range control.accumulator(&board).sums {
+		if sum != fresh.sums[j] {
+			t.Fatal("After taking every move back the accumulator is off from a fresh one")
+		}
+	}
+}
+
+func TestNetwork_ForgetsBoards(t *testing.T) {
+	control := newSearchControl(nil, SearchLimits{}, SearchOptions{Network: NewNetwork(16, 8, 1)}, nil)
+	defer control.finish()
+	board := NewDefaultBoard()
+	player := Player(HUMAN)
+	pv := Moves{}
+	board.NegamaxMulti(&player, 4, 0, bestMin, bestMax, nil, 0, control, &pv)
+	kept := 0
+	control.accumulators.Range(func(key, value interface{}) bool {
+		kept++
+		return true
+	})
+	if kept > 1 {
+		t.Error("The accumulators of the board copies should be dropped after the search, kept", kept)
+	}
+}
+
+func TestNetwork_FirstLayerCantOverflow(t *testing.T) {
+	// A board full of pieces, on the biggest board there can be
+	defer SetVariant(Variants[0])
+	if err := SetVariant(Variant{Name: "full", Cols: maxBoardCols, Rows: maxBoardRows, Start: "8/8/8/8/8/8/8/8/8 h"}); err != nil {
+		t.Fatal(err)
+	}
+	board := Board{}
+	for row := range board {
+		for col := range board[row] {
+			board[row][col] = BISHOP_GOB
+		}
+	}
+
+	network := newZeroNetwork(4, 2)
+	for i := range network.weights1 {
+		network.weights1[i] = 100
+	}
+	for i := range network.biases1 {
+		network.biases1[i] = 100
+	}
+	network.quantize()
+	if network.weights1[0] != maxFirstLayerWeight {
+		t.Errorf("Weights should be cut down to %v, got %v", maxFirstLayerWeight, network.weights1[0])
+	}
+	for _, sum := range network.newAccumulator(&board).sums {
+		if sum <= 0 {
+			t.Fatal("The sums of a full board shouldn't overflow, got", sum)
+		}
+	}
+}
+
+func TestNetwork_QuantizedMatchesFloat(t *testing.T) {
+	network := NewNetwork(32, 16, 2)
+	for _, position := range searchPositions {
+		board, _, err := ParsePosition(position)
+		if err != nil {
+			t.Fatal(err)
+		}
+		exact := network.Evaluate(&board)
+		quantized := network.newAccumulator(&board).score(network)
+		if math.Abs(float64(exact-quantized)) > 0.25 {
+			t.Errorf("%s: the int16 layer should score close to %v, got %v", position, exact, quantized)
+		}
+	}
+}
+
+func TestNetwork_WriteRead(t *testing.T) {
+	network := NewNetwork(8, 4, 3)
+	var buffer bytes.Buffer
+	if err := network.Write(&buffer); err != nil {
+		t.Fatal(err)
+	}
+	written := buffer.Bytes()
+	read, err := ReadNetwork(bytes.NewReader(written))
+	if err != nil {
+		t.Fatal(err)
+	}
+	board := NewDefaultBoard()
+	if read.Evaluate(&board) != network.Evaluate(&board) {
+		t.Error("The network read back should score like the one written")
+	}
+
+	if _, err := ReadNetwork(bytes.NewReader(written[:len(written)-1])); err == nil {
+		t.Error("A cut short file should be an error")
+	}
+	if _, err := ReadNetwork(bytes.NewReader([]byte("not weights"))); err == nil {
+		t.Error("Something that isn't a weights file should be an error")
+	}
+	if network, err := ParseEvaluator("classic"); network != nil || err != nil {
+		t.Error("The classic evaluator should be no network")
+	}
+}
+
+func TestNetwork_Train(t *testing.T) {
+	network := NewNetwork(16, 8, 4)
+	gobot := Player(GOBOT)
+	var positions []TrainingPosition
+	// Made up results, so there is something to learn besides the material. The start position is left out,
+	// since it is the same with the sides swapped and couldn't be learned as a win for either side
+	for i, position := range searchPositions[1:] {
+		board, _, err := ParsePosition(position)
+		if err != nil {
+			t.Fatal(err)
+		}
+		positions = append(positions, TrainingPosition{Board: board, Score: board.GetWeightedScoreForPlayer(&gobot), Result: float32(i % 2)})
+	}
+	options := TrainingOptions{Epochs: 50, LearningRate: 0.2, ResultWeight: 0.5, Seed: 1}
+	before := network.Loss(positions, options.ResultWeight)
+	network.Train(positions, options, nil)
+	if after := network.Loss(positions, options.ResultWeight); after >= before/2 {
+		t.Errorf("Training should at least halve the loss, went from %v to %v", before, after)
+	}
+}
+
+func TestNetwork_Search(t *testing.T) {
+	SetDebug(false)
+	board := NewDefaultBoard()
+	player := Player(HUMAN)
+	options := SearchOptions{Network: NewNetwork(16, 8, 5), Deterministic: true}
+	best := board.SearchWithOptions(&player, SearchLimits{Depth: 4}, options, nil, nil)
+	if !board.IsValidMoveForPlayer(best.Move(), player) {
+		t.Error("The search with a network should find a legal move, got " + best.Move().ToString())
+	}
+	again := board.SearchWithOptions(&player, SearchLimits{Depth: 4}, options, nil, nil)
+	if *again.Score() != *best.Score() {
+		t.Error("A deterministic search with a network should score the same every time")
+	}
+	// Spread over goroutines, each with accumulators of its own
+	options.Deterministic = false
+	parallel := board.SearchWithOptions(&player, SearchLimits{Depth: 4}, options, nil, nil)
+	if !board.IsValidMoveForPlayer(parallel.Move(), player) {
+		t.Error("The parallel search with a network should find a legal move, got " + parallel.Move().ToString())
+	}
+}
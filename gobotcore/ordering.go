@@ -0,0 +1,112 @@
+package gobotcore
+
+import (
+	"sort"
+	"sync/atomic"
+)
+
+// Plies the ordering tables have room for. Every ply uses up at least one depth, so searches never go deeper than MaxSearchDepth
+const maxPly = int(MaxSearchDepth) + 1
+
+// Sort keys, from first to last: the previous iteration's PV move, captures, killer moves and then quiet moves by history
+const (
+	pvMoveScore   int64  = 1 << 40
+	captureScore  int64  = 1 << 30
+	killerScore   int64  = 1 << 29
+	maxHistory    int64  = killerScore - 2
+	numSquares           = int(maxBoardRows) * int(maxBoardCols)
+	encodedMarker uint32 = 1 << 16 // So that no move encodes to 0, which is an empty killer slot
+)
+
+// Tables that learn which moves are good during a search. Shared by every goroutine of the search
+type moveOrdering struct {
+	killers [maxPly][2]uint32             // Quiet moves that caused a cutoff at each ply. Accessed atomically
+	history [numSquares][numSquares]int64 // Indexed by from and to square. Accessed atomically
+	pv      Moves                         // Best line of the previous iteration
+}
+
+func squareIndex(location Location) int {
+	return int(location.row)*int(maxBoardCols) + int(location.col)
+}
+
+func encodeMove(move Move) uint32 {
+	return encodedMarker | uint32(squareIndex(move.from))<<8 | uint32(squareIndex(move.to))
+}
+
+// Sorts moves so that the ones most likely to cause a cutoff are searched first
+func (control *searchControl) orderMoves(board *Board, moves Moves, ply int) {
+	if control.options.NoMoveOrdering {
+		sort.Sort(moves)
+		return
+	}
+	scored := scoredMoves{moves: moves, scores: make([]int64, len(moves))}
+	for i, move := range moves {
+		scored.scores[i] = control.ordering.score(board, move, ply)
+	}
+	sort.Stable(scored)
+}
+
+func (ordering *moveOrdering) score(board *Board, move Move, ply int) int64 {
+	if ply < len(ordering.pv) && move.Equals(&ordering.pv[ply]) {
+		return pvMoveScore
+	}
+
+	// Most valuable victim first, and the least valuable attacker among captures of the same victim.
+	// Captures that lose material in the exchange that follows go after the quiet moves
+	victim := board.PieceAt(&move.to)
+	if !victim.IsEmpty() {
+		if board.canLoseExchange(move) {
+			if exchange := board.StaticExchange(move); exchange < 0 {
+				return int64(exchange * 100)
+			}
+		}
+		attacker := board.PieceAt(&move.from)
+		return captureScore + int64(victim.Weight()*100) - int64(attacker.Weight())
+	}
+
+	encoded := encodeMove(move)
+	if atomic.LoadUint32(&ordering.killers[ply][0]) == encoded {
+		return killerScore
+	}
+	if atomic.LoadUint32(&ordering.killers[ply][1]) == encoded {
+		return killerScore - 1
+	}
+	return atomic.LoadInt64(&ordering.history[squareIndex(move.from)][squareIndex(move.to)])
+}
+
+// Remembers a move that caused a cutoff. Captures are already searched early, so only quiet moves are kept
+func (control *searchControl) recordCutoff(move Move, takenPiece Piece, ply int, depth int8) {
+	if takenPiece != EMPTY || control.options.NoMoveOrdering {
+		return
+	}
+	ordering := control.ordering
+	encoded := encodeMove(move)
+	if first := atomic.LoadUint32(&ordering.killers[ply][0]); first != encoded {
+		atomic.StoreUint32(&ordering.killers[ply][1], first)
+		atomic.StoreUint32(&ordering.killers[ply][0], encoded)
+	}
+
+	entry := &ordering.history[squareIndex(move.from)][squareIndex(move.to)]
+	if atomic.AddInt64(entry, int64(depth)*int64(depth)) > maxHistory {
+		atomic.StoreInt64(entry, maxHistory)
+	}
+}
+
+type scoredMoves struct {
+	moves  Moves
+	scores []int64
+}
+
+func (scored scoredMoves) Len() int {
+	return len(scored.moves)
+}
+
+// Higher scores go first
+func (scored scoredMoves) Less(i, j int) bool {
+	return scored.scores[i] > scored.scores[j]
+}
+
+func (scored scoredMoves) Swap(i, j int) {
+	scored.moves[i], scored.moves[j] = scored.moves[j], scored.moves[i]
+	scored.scores[i], scored.scores[j] = scored.scores[j], scored.scores[i]
+}
@@ -0,0 +1,86 @@
+package gobotcore
+
+import "testing"
+
+func TestMoveOrdering_Order(t *testing.T) {
+	// Human's bishop on C2 can take the rook on B1. Human's pawn on C3 and knight on A2 can take the pawn on B4
+	board, _, err := ParsePosition("K5/6/6/6/1P4/2p3/n1b3/1R2k1 h")
+	if err != nil {
+		t.Fatal(err)
+	}
+	control := newSearchControl(nil, SearchLimits{}, SearchOptions{}, nil)
+	defer control.finish()
+	control.ordering.pv = Moves{Move{}, NewMoveFromString("E1D1")}
+	control.recordCutoff(NewMoveFromString("C2D3"), EMPTY, 1, 3)
+
+	moves := board.LegalMovesForPlayer(HUMAN)
+	control.orderMoves(&board, moves, 1)
+	expected := []string{"E1D1", "C2B1", "C3B4", "A2B4", "C2D3"}
+	for i, str := range expected {
+		if moves[i].ToString() != str {
+			t.Fatalf("Move %d should be %s, order is %s", i, str, moves.ToString())
+		}
+	}
+}
+
+func TestMoveOrdering_Killers(t *testing.T) {
+	control := newSearchControl(nil, SearchLimits{}, SearchOptions{}, nil)
+	defer control.finish()
+	first, second := NewMoveFromString("C3C4"), NewMoveFromString("D3D4")
+	control.recordCutoff(first, EMPTY, 2, 4)
+	control.recordCutoff(second, EMPTY, 2, 4)
+	control.recordCutoff(second, EMPTY, 2, 4)
+	control.recordCutoff(NewMoveFromString("A2B4"), KNIGHT_GOB, 2, 4)
+
+	killers := control.ordering.killers[2]
+	if killers[0] != encodeMove(second) || killers[1] != encodeMove(first) {
+		t.Error("Newest killer should be first without pushing out the other one, got", killers)
+	}
+	if history := control.ordering.history[squareIndex(second.from)][squareIndex(second.to)]; history != 32 {
+		t.Error("History should add depth squared for every cutoff, got", history)
+	}
+}
+
+// Searches to a fixed depth and returns the nodes it took
+func nodesToDepth(position string, depth int8, options SearchOptions) int64 {
+	board, player, _ := ParsePosition(position)
+	var nodes int64
+	board.SearchWithOptions(&player, SearchLimits{Depth: depth}, options, nil, func(info SearchInfo) {
+		nodes = info.Nodes
+	})
+	return nodes
+}
+
+var orderingPositions = []string{
+	StartPosition,
+	"1K4/NBR1BN/2PP2/6/3p2/2p3/nbrrbn/4k1 g",
+}
+
+func TestMoveOrdering_FewerNodes(t *testing.T) {
+	for _, position := range orderingPositions {
+		ordered := nodesToDepth(position, 6, SearchOptions{})
+		plain := nodesToDepth(position, 6, SearchOptions{NoMoveOrdering: true})
+		if ordered >= plain {
+			t.Errorf("Move ordering should search fewer nodes in %s: %d with ordering, %d without", position, ordered, plain)
+		}
+	}
+}
+
+func benchmarkNodes(b *testing.B, options SearchOptions) {
+	var nodes int64
+	for i := 0; i < b.N; i++ {
+		for _, position := range orderingPositions {
+			nodes += nodesToDepth(position, 6, options)
+		}
+	}
+	b.ReportMetric(float64(nodes)/float64(b.N), "nodes/op")
+}
+
+// go test -run none -bench Nodes ./gobotcore compares the nodes needed to reach depth 6
+func BenchmarkNodes_MoveOrdering(b *testing.B) {
+	benchmarkNodes(b, SearchOptions{})
+}
+
+func BenchmarkNodes_WeightOrdering(b *testing.B) {
+	benchmarkNodes(b, SearchOptions{NoMoveOrdering: true})
+}
@@ -22,7 +22,7 @@ func (piece *Piece) GetName() string {
 	case EMPTY:
 		return "-"
 	case BISHOP_GOB:
-		return "B" //TODO - make these unicode for the competition?
+		return "B"
 	case BISHOP_HUM:
 		return "b"
 	case ROOK_GOB:
@@ -45,32 +45,84 @@ func (piece *Piece) GetName() string {
 	panic("Unknown piece")
 }
 
+// Unicode chess glyph. Gobot's pieces are black and Human's are white
+func (piece *Piece) Glyph() string {
+	switch *piece {
+	case EMPTY:
+		return "·"
+	case BISHOP_GOB:
+		return "♝"
+	case BISHOP_HUM:
+		return "♗"
+	case ROOK_GOB:
+		return "♜"
+	case ROOK_HUM:
+		return "♖"
+	case KNIGHT_GOB:
+		return "♞"
+	case KNIGHT_HUM:
+		return "♘"
+	case PAWN_GOB:
+		return "♟"
+	case PAWN_HUM:
+		return "♙"
+	case KING_GOB:
+		return "♚"
+	case KING_HUM:
+		return "♔"
+	}
+	panic("Unknown piece")
+}
+
+func (piece Piece) LongName() string {
+	switch piece {
+	case BISHOP_GOB, BISHOP_HUM:
+		return "Bishop"
+	case ROOK_GOB, ROOK_HUM:
+		return "Rook"
+	case KNIGHT_GOB, KNIGHT_HUM:
+		return "Knight"
+	case PAWN_GOB, PAWN_HUM:
+		return "Pawn"
+	case KING_GOB, KING_HUM:
+		return "King"
+	}
+	return "Empty"
+}
+
 func GetPieceByName(name string) Piece {
+	if piece, ok := pieceByName(name); ok {
+		return piece
+	}
+	panic("Unknown name")
+}
+
+func pieceByName(name string) (Piece, bool) {
 	switch name {
 	case "-":
-		return EMPTY
+		return EMPTY, true
 	case "B":
-		return BISHOP_GOB
+		return BISHOP_GOB, true
 	case "b":
-		return BISHOP_HUM
+		return BISHOP_HUM, true
 	case "R":
-		return ROOK_GOB
+		return ROOK_GOB, true
 	case "r":
-		return ROOK_HUM
+		return ROOK_HUM, true
 	case "N":
-		return KNIGHT_GOB
+		return KNIGHT_GOB, true
 	case "n":
-		return KNIGHT_HUM
+		return KNIGHT_HUM, true
 	case "P":
-		return PAWN_GOB
+		return PAWN_GOB, true
 	case "p":
-		return PAWN_HUM
+		return PAWN_HUM, true
 	case "K":
-		return KING_GOB
+		return KING_GOB, true
 	case "k":
-		return KING_HUM
+		return KING_HUM, true
 	}
-	panic("Unknown name")
+	return EMPTY, false
 }
 
 func (piece Piece) IsOwnedBy(player *Player) bool {
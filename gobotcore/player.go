@@ -14,3 +14,18 @@ func (player *Player) Opponent() *Player {
 	}
 	return &newPlayer
 }
+
+func (player Player) Name() string {
+	if player == GOBOT {
+		return "Gobot"
+	}
+	return "Human"
+}
+
+// Used for the side to move in positions
+func (player Player) Char() string {
+	if player == GOBOT {
+		return "g"
+	}
+	return "h"
+}
@@ -0,0 +1,40 @@
+package gobotcore
+
+/* Ponder searches on the opponent's time. After Gobot moves, it searches the position after the reply it expects,
+ * for its own next move. If the opponent plays that reply (a ponder hit), the search goes on with everything it has
+ * already done, and only then do its time limits start counting. Otherwise the search is stopped and thrown away
+ */
+type Ponder struct {
+	board   Board
+	limits  SearchLimits
+	control *searchControl
+	result  chan ScoredMove
+}
+
+// StartPonder starts searching for player's move in the background, without a time limit until Hit is called.
+// The search ends early if its depth or node limit is reached or stop is closed. stop and info may be nil
+func (board *Board) StartPonder(player *Player, limits SearchLimits, options SearchOptions, stop <-chan struct{}, info func(SearchInfo)) *Ponder {
+	ponder := &Ponder{board: *board, limits: limits, result: make(chan ScoredMove, 1)}
+	untimed := limits
+	untimed.MoveTime, untimed.Clock = 0, 0
+	ponder.control = newSearchControl(board, untimed, options, stop)
+
+	side := *player
+	go func() {
+		best, _ := ponder.board.deepen(&side, limits, ponder.control, info)
+		ponder.result <- best
+	}()
+	return ponder
+}
+
+// Hit tells the ponder that the opponent played the expected move, so the time limits start counting from now
+func (ponder *Ponder) Hit() {
+	ponder.control.startClock(&ponder.board, ponder.limits)
+}
+
+// Wait blocks until the search is over and returns its result
+func (ponder *Ponder) Wait() ScoredMove {
+	result := <-ponder.result
+	ponder.result <- result // So that Wait can be called again
+	return result
+}
@@ -0,0 +1,89 @@
+package gobotcore
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// A position is written on one line, like a chess FEN:
+// the rows from the top (row 8) to the bottom separated by '/', using the piece names from GetName
+// and a digit for a run of empty squares, followed by the side to move ('g' for Gobot, 'h' for Human).
+// This is the start of the standard variant, CurrentVariant().Start is the start of the one being played
+const StartPosition = "1K4/NBRRBN/2PP2/6/6/2pp2/nbrrbn/4k1 h"
+
+func ParsePosition(position string) (Board, Player, error) {
+	board := NewEmptyBoard()
+	fields := strings.Fields(position)
+	if len(fields) != 2 {
+		return board, HUMAN, errors.New("position must have a board and a side to move")
+	}
+
+	rows := strings.Split(fields[0], "/")
+	if len(rows) != int(boardRows) {
+		return board, HUMAN, errors.New("position must have " + strconv.Itoa(int(boardRows)) + " rows")
+	}
+	for i, rowString := range rows {
+		row := boardRows - 1 - int8(i)
+		tooMany := errors.New("row " + strconv.Itoa(int(row+1)) + " has too many columns")
+		col := 0 // An int, so a long run of digits can't wrap around
+		for _, char := range rowString {
+			if char >= '1' && char <= '9' {
+				col += int(char - '0')
+				if col > int(boardCols) {
+					return board, HUMAN, tooMany
+				}
+				continue
+			}
+			piece, ok := pieceByName(string(char))
+			if !ok || piece == EMPTY {
+				return board, HUMAN, errors.New("unknown piece " + string(char))
+			}
+			if col >= int(boardCols) {
+				return board, HUMAN, tooMany
+			}
+			board[row][col] = piece
+			col++
+		}
+		if col != int(boardCols) {
+			return board, HUMAN, errors.New("row " + strconv.Itoa(int(row+1)) + " must have " + strconv.Itoa(int(boardCols)) + " columns")
+		}
+	}
+
+	switch fields[1] {
+	case "g":
+		return board, GOBOT, nil
+	case "h":
+		return board, HUMAN, nil
+	}
+	return board, HUMAN, errors.New("side to move must be g or h")
+}
+
+func (board *Board) Position(toMove Player) string {
+	var builder strings.Builder
+	var row, col int8
+	for row = boardRows - 1; row >= 0; row-- {
+		empty := 0
+		for col = 0; col < boardCols; col++ {
+			piece := board[row][col]
+			if piece.IsEmpty() {
+				empty++
+				continue
+			}
+			if empty > 0 {
+				builder.WriteString(strconv.Itoa(empty))
+				empty = 0
+			}
+			builder.WriteString(piece.GetName())
+		}
+		if empty > 0 {
+			builder.WriteString(strconv.Itoa(empty))
+		}
+		if row > 0 {
+			builder.WriteString("/")
+		}
+	}
+	builder.WriteString(" ")
+	builder.WriteString(toMove.Char())
+	return builder.String()
+}
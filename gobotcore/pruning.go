@@ -0,0 +1,58 @@
+package gobotcore
+
+const (
+	// Null-move searches are this much shallower than a normal search of the node, and only tried with this much depth left
+	nullMoveReduction int8 = 2
+	nullMoveMinDepth  int8 = nullMoveReduction + 2
+
+	// Late move reductions search quiet moves after the first few one ply shallower, as long as there is this much depth left
+	lmrFullMoves      = 3
+	lmrMinDepth  int8 = 3
+
+	// A quiet move one ply from the leaves is skipped if the position scores at least this much below the best score so far.
+	// Quiet moves never change the material, since every piece except the pawn and the king has the same weight
+	futilityMargin float32 = 10
+)
+
+// Scores the position for player the same way a leaf does, with the mobility of both sides.
+// A network has learned what mobility is worth on its own
+func (control *searchControl) staticScore(board *Board, player *Player, playerMoves Moves, numParentMoves int) float32 {
+	if control.options.Network != nil {
+		return control.evaluate(board, player)
+	}
+	return mobilityWeight*float32(len(playerMoves)) - mobilityWeight*float32(numParentMoves) + control.evaluate(board, player)
+}
+
+func (control *searchControl) canNullMove(allowNull bool, depth int8) bool {
+	return allowNull && !control.options.NoNullMove && depth >= nullMoveMinDepth
+}
+
+func (control *searchControl) canReduce(moveIndex int, newDepth int8, takenPiece Piece) bool {
+	return !control.options.NoLateMoveReductions && moveIndex >= lmrFullMoves && newDepth >= lmrMinDepth && takenPiece == EMPTY
+}
+
+// True if quiet moves of a node whose children are leaves can be pruned
+func (control *searchControl) canPruneFutile(newDepth int8) bool {
+	return !control.options.NoFutility && newDepth == 1
+}
+
+func (control *searchControl) futilityMargin() float32 {
+	return futilityMargin + control.options.Mistakes
+}
+
+/* Null-move pruning. The player passes and the opponent gets to move twice in a row.
+ * If the score still reaches beta, a real move would most likely do even better, so the node can be cut.
+ * Passing isn't allowed in Morph though, and sometimes every real move is worse than passing.
+ * So the cutoff is only taken if a shallower normal search of the node agrees. That search is returned with true
+ */
+func (board *Board) nullMove(player *Player, depth int8, ply int, beta float32, stopChan <-chan struct{}, numMoves int, numParentMoves int, control *searchControl) (float32, bool) {
+	control.options.Trace.setLabel("null")
+	score := -board.Negamax(player.Opponent(), depth-1-nullMoveReduction, ply+1, -beta, -zeroWindowAlpha(beta), stopChan, numMoves, control, &Moves{}, false)
+	if score < beta || control.isOver() {
+		return score, false
+	}
+
+	control.options.Trace.setLabel("verify")
+	verified := board.Negamax(player, depth-nullMoveReduction, ply, zeroWindowAlpha(beta), beta, stopChan, numParentMoves, control, &Moves{}, false)
+	return verified, verified >= beta && !control.isOver()
+}
@@ -0,0 +1,56 @@
+package gobotcore
+
+import "testing"
+
+var pruningOptions = map[string]SearchOptions{
+	"all pruning":   {},
+	"no null move":  {NoNullMove: true},
+	"no reductions": {NoLateMoveReductions: true},
+	"no futility":   {NoFutility: true},
+	"no pruning":    {NoNullMove: true, NoLateMoveReductions: true, NoFutility: true},
+}
+
+func TestPruning_FewerNodes(t *testing.T) {
+	for _, position := range orderingPositions {
+		pruned := nodesToDepth(position, 6, pruningOptions["all pruning"])
+		full := nodesToDepth(position, 6, pruningOptions["no pruning"])
+		if pruned >= full {
+			t.Errorf("Pruning should search fewer nodes in %s: %d with pruning, %d without", position, pruned, full)
+		}
+	}
+}
+
+func TestPruning_KeepsTactics(t *testing.T) {
+	// The position of TestBoard_Minimax3. Gobot's rook on D7 should take Human's rook on D8
+	board, player, err := ParsePosition("1K1r2/NBRR2/2PP2/6/6/2pp2/nb1r2/4k1 g")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := NewMoveFromString("D7D8")
+	for name, options := range pruningOptions {
+		move := board.SearchWithOptions(&player, SearchLimits{Depth: 6}, options, nil, nil)
+		if !move.Move().Equals(&expected) {
+			t.Errorf("With %s the move should be %s, got %s", name, expected.ToString(), move.Move().ToString())
+		}
+	}
+}
+
+func BenchmarkNodes_Pruning(b *testing.B) {
+	benchmarkNodes(b, pruningOptions["all pruning"])
+}
+
+func BenchmarkNodes_NoNullMove(b *testing.B) {
+	benchmarkNodes(b, pruningOptions["no null move"])
+}
+
+func BenchmarkNodes_NoLateMoveReductions(b *testing.B) {
+	benchmarkNodes(b, pruningOptions["no reductions"])
+}
+
+func BenchmarkNodes_NoFutility(b *testing.B) {
+	benchmarkNodes(b, pruningOptions["no futility"])
+}
+
+func BenchmarkNodes_NoPruning(b *testing.B) {
+	benchmarkNodes(b, pruningOptions["no pruning"])
+}
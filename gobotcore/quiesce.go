@@ -0,0 +1,67 @@
+package gobotcore
+
+import "sort"
+
+/* Quiescence search, which scores the leaves of Negamax. A leaf in the middle of an exchange would be scored
+ * as if the last capture couldn't be answered, so the leaves keep searching captures until the position is quiet.
+ * The player to move can always stand pat on the static score instead, since nobody has to capture.
+ * Captures that lose material by static exchange evaluation are skipped, since standing pat is better.
+ * Every capture takes a piece off the board, so the search always ends
+ */
+func (board *Board) quiesce(player *Player, ply int, alpha float32, beta float32, playerMoves Moves, numParentMoves int, control *searchControl) float32 {
+	standPat := control.staticScore(board, player, playerMoves, numParentMoves)
+	if control.options.NoQuiescence || standPat >= beta || ply >= maxPly-1 {
+		return standPat
+	}
+	if standPat > alpha {
+		alpha = standPat
+	}
+
+	captures := board.goodCaptures(playerMoves)
+	bestScore := standPat
+	opponent := player.Opponent()
+	for _, move := range captures {
+		control.countNode()
+		takenPiece := control.makeMove(board, &move)
+		opponentMoves := board.LegalMovesForPlayer(*opponent)
+		var score float32
+		if board.IsGameOverForPlayer(opponent, &opponentMoves) {
+			score = -lossScore(ply + 1)
+		} else {
+			score = -board.quiesce(opponent, ply+1, -beta, -alpha, opponentMoves, len(playerMoves), control)
+		}
+		control.retractMove(board, &move, takenPiece)
+
+		if score > bestScore {
+			bestScore = score
+		}
+		if bestScore >= beta || control.isOver() {
+			return bestScore
+		}
+		if bestScore > alpha {
+			alpha = bestScore
+		}
+	}
+	return bestScore
+}
+
+// The captures that don't lose material in the exchange, the ones that gain the most first
+func (board *Board) goodCaptures(moves Moves) Moves {
+	captures := scoredMoves{}
+	for _, move := range moves {
+		victim := board.PieceAt(&move.to)
+		if victim.IsEmpty() {
+			continue
+		}
+		gain := victim.Weight()
+		if board.canLoseExchange(move) {
+			if gain = board.StaticExchange(move); gain < 0 {
+				continue
+			}
+		}
+		captures.moves = append(captures.moves, move)
+		captures.scores = append(captures.scores, int64(gain*100))
+	}
+	sort.Stable(captures)
+	return captures.moves
+}
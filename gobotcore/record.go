@@ -0,0 +1,87 @@
+package gobotcore
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"os"
+	"strings"
+)
+
+/* A game record is a text file with the starting position followed by one move per line:
+ *	start 1K4/NBRRBN/2PP2/6/6/2pp2/nbrrbn/4k1 h
+ *	C3C4
+ *	D7D6
+ * Blank lines and lines starting with # are ignored
+ */
+
+func (game *Game) WriteRecord(w io.Writer) error {
+	writer := bufio.NewWriter(w)
+	writer.WriteString("start " + game.start.Position(game.startTurn) + "\n")
+	for _, played := range game.history {
+		writer.WriteString(played.Move.ToString() + "\n")
+	}
+	return writer.Flush()
+}
+
+func ReadGameRecord(r io.Reader) (*Game, error) {
+	var game *Game
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if game == nil {
+			if !strings.HasPrefix(line, "start ") {
+				return nil, errors.New("game record must begin with the start position")
+			}
+			var err error
+			if game, err = NewGameFromPosition(strings.TrimPrefix(line, "start ")); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		move, err := ParseMove(line)
+		if err == nil {
+			err = game.MakeMove(move)
+		}
+		if err != nil {
+			return nil, errors.New("bad move " + line + " in game record: " + err.Error())
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if game == nil {
+		return nil, errors.New("game record is empty")
+	}
+	return game, nil
+}
+
+// Writes the record to a temporary file first so a crash never leaves a half written save behind
+func (game *Game) SaveRecord(path string) error {
+	tmpPath := path + ".tmp"
+	file, err := os.Create(tmpPath)
+	if err != nil {
+		return err
+	}
+	if err := game.WriteRecord(file); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, path)
+}
+
+func LoadGameRecord(path string) (*Game, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return ReadGameRecord(file)
+}
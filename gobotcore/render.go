@@ -0,0 +1,182 @@
+package gobotcore
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+type RenderStyle int8
+
+const (
+	StyleASCII   = RenderStyle(iota) // Piece letters, like GetName
+	StyleUnicode                     // Chess glyphs, black for Gobot and white for Human
+	StyleColor                       // Chess glyphs on ANSI colored squares
+)
+
+type CoordinateStyle int8
+
+const (
+	CoordinatesEdges  = CoordinateStyle(iota) // Row numbers on the left, column letters below
+	CoordinatesNone                           // Just the board
+	CoordinatesAround                         // Row numbers and column letters on every side
+)
+
+// ANSI escape codes used by StyleColor
+const (
+	ansiReset      = "\x1b[0m"
+	ansiDark       = "\x1b[48;5;94m"
+	ansiLight      = "\x1b[48;5;180m"
+	ansiHighlight  = "\x1b[48;5;139m"
+	ansiGobotPiece = "\x1b[38;5;16m"
+	ansiHumanPiece = "\x1b[38;5;231m"
+)
+
+// Renderer draws boards as text. The zero value draws boards the way PrintBoard always has
+type Renderer struct {
+	Style       RenderStyle
+	Coordinates CoordinateStyle
+	// Draw the board from Gobot's side, with the coordinates used by ToStringFlipped
+	Flipped bool
+	// The squares of this move are highlighted. With StyleASCII and StyleUnicode they get a * after the piece
+	LastMove *Move
+}
+
+var defaultRenderer = Renderer{}
+
+// Sets the renderer used by PrintBoard
+func SetRenderer(renderer Renderer) {
+	defaultRenderer = renderer
+}
+
+func ParseRenderStyle(name string) (RenderStyle, error) {
+	switch strings.ToLower(name) {
+	case "ascii":
+		return StyleASCII, nil
+	case "unicode":
+		return StyleUnicode, nil
+	case "color":
+		return StyleColor, nil
+	}
+	return StyleASCII, errors.New("style must be ascii, unicode or color")
+}
+
+func ParseCoordinateStyle(name string) (CoordinateStyle, error) {
+	switch strings.ToLower(name) {
+	case "edges":
+		return CoordinatesEdges, nil
+	case "none":
+		return CoordinatesNone, nil
+	case "around":
+		return CoordinatesAround, nil
+	}
+	return CoordinatesEdges, errors.New("coordinates must be edges, none or around")
+}
+
+func (renderer Renderer) Print(board *Board) {
+	fmt.Print(renderer.Render(board))
+}
+
+func (renderer Renderer) Render(board *Board) string {
+	/*Renders a board like so by default
+	8   - K - - - -
+	7   N B R R B N
+	6   - - P P - -
+	5   - - - - - -
+	4   - - - - - -
+	3   - - p p - -
+	2   n n r r b n
+	1   - - - - k -
+
+	    A B C D E F
+	*/
+	var builder strings.Builder
+	builder.WriteString("\n")
+	if renderer.Coordinates == CoordinatesAround {
+		builder.WriteString(renderer.columnLabels() + "\n\n")
+	}
+
+	var i, j int8
+	for i = 0; i < boardRows; i++ {
+		row := boardRows - 1 - i
+		if renderer.Flipped {
+			row = i
+		}
+		if renderer.Coordinates != CoordinatesNone {
+			builder.WriteString(renderer.rowLabel(row) + "   ")
+		}
+		for j = 0; j < boardCols; j++ {
+			col := j
+			if renderer.Flipped {
+				col = boardCols - 1 - j
+			}
+			builder.WriteString(renderer.square(board, Location{col: col, row: row}))
+		}
+		if renderer.Coordinates == CoordinatesAround {
+			builder.WriteString("  " + renderer.rowLabel(row))
+		}
+		builder.WriteString("\n")
+	}
+
+	if renderer.Coordinates != CoordinatesNone {
+		builder.WriteString("\n" + renderer.columnLabels() + "\n")
+	}
+	builder.WriteString("\n")
+	return builder.String()
+}
+
+func (renderer Renderer) square(board *Board, location Location) string {
+	piece := board.PieceAt(&location)
+	highlighted := renderer.LastMove != nil && (location.Equals(&renderer.LastMove.from) || location.Equals(&renderer.LastMove.to))
+
+	switch renderer.Style {
+	case StyleColor:
+		background := ansiLight
+		if highlighted {
+			background = ansiHighlight
+		} else if (location.col+location.row)%2 == 0 {
+			background = ansiDark
+		}
+		foreground := ansiHumanPiece
+		gobot := Player(GOBOT)
+		if piece.IsOwnedBy(&gobot) {
+			foreground = ansiGobotPiece
+		}
+		glyph := piece.Glyph()
+		if piece.IsEmpty() {
+			glyph = " "
+		}
+		return background + foreground + " " + glyph + " " + ansiReset
+	case StyleUnicode:
+		if highlighted {
+			return piece.Glyph() + "*"
+		}
+		return piece.Glyph() + " "
+	}
+	if highlighted {
+		return piece.GetName() + "*"
+	}
+	return piece.GetName() + " "
+}
+
+func (renderer Renderer) rowLabel(row int8) string {
+	if renderer.Flipped {
+		return strconv.Itoa(int(boardRows - row))
+	}
+	return strconv.Itoa(int(row + 1))
+}
+
+// A flipped board is relabeled from Gobot's side, so the labels read the same either way
+func (renderer Renderer) columnLabels() string {
+	labels := make([]string, boardCols)
+	for i := range labels {
+		labels[i] = string(alphabet[i])
+	}
+
+	if renderer.Style == StyleColor {
+		// Squares are three characters wide
+		return "    " + " " + strings.Join(labels, "  ")
+	}
+	return "    " + strings.Join(labels, " ")
+}
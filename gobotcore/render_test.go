@@ -0,0 +1,64 @@
+package gobotcore
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestRenderer_Default(t *testing.T) {
+	var buffer bytes.Buffer
+	buffer.WriteString("\n")
+	buffer.WriteString("8   - K - - - - \n")
+	buffer.WriteString("7   N B R R B N \n")
+	buffer.WriteString("6   - - P P - - \n")
+	buffer.WriteString("5   - - - - - - \n")
+	buffer.WriteString("4   - - - - - - \n")
+	buffer.WriteString("3   - - p p - - \n")
+	buffer.WriteString("2   n b r r b n \n")
+	buffer.WriteString("1   - - - - k - \n")
+	buffer.WriteString("\n")
+	buffer.WriteString("    A B C D E F\n")
+	buffer.WriteString("\n")
+	board := NewDefaultBoard()
+	if rendered := (Renderer{}).Render(&board); rendered != buffer.String() {
+		t.Error("Default renderer should match the old PrintBoard output, got:\n" + rendered)
+	}
+}
+
+func TestRenderer_FlippedMatchesToStringFlipped(t *testing.T) {
+	board := NewDefaultBoard()
+	move := NewMoveFromString("B8C8")
+	renderer := Renderer{Flipped: true, LastMove: &move}
+	lines := strings.Split(renderer.Render(&board), "\n")
+
+	// Gobot's king is drawn in the bottom row, at the square ToStringFlipped calls E1
+	if lines[8] != "1   - - - -*K*- " {
+		t.Error("Wrong bottom row: " + lines[8])
+	}
+	if flipped := move.ToStringFlipped(); flipped != "E1D1" {
+		t.Error("Flipped move should be E1D1, is " + flipped)
+	}
+	if lines[1] != "8   - k - - - - " {
+		t.Error("Human's king should be at the top: " + lines[1])
+	}
+}
+
+func TestRenderer_Styles(t *testing.T) {
+	board := NewDefaultBoard()
+	unicode := Renderer{Style: StyleUnicode, Coordinates: CoordinatesNone}.Render(&board)
+	if !strings.HasPrefix(unicode, "\n· ♚ · · · · \n") {
+		t.Error("Unexpected unicode board: " + unicode)
+	}
+	around := Renderer{Coordinates: CoordinatesAround}.Render(&board)
+	if !strings.Contains(around, "8   - K - - - -   8\n") || !strings.HasPrefix(around, "\n    A B C D E F\n") {
+		t.Error("Unexpected coordinates: " + around)
+	}
+	color := Renderer{Style: StyleColor}.Render(&board)
+	if !strings.Contains(color, ansiDark) || !strings.Contains(color, "♚") {
+		t.Error("Color board should have colored squares and glyphs")
+	}
+	if _, err := ParseRenderStyle("fancy"); err == nil {
+		t.Error("Unknown styles should be rejected")
+	}
+}
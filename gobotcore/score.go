@@ -0,0 +1,67 @@
+package gobotcore
+
+import (
+	"fmt"
+	"math"
+)
+
+// Wins and losses score winMax or winMin, minus or plus the plies from the root to the king capture.
+// So quicker wins score higher, and slower losses score higher than quick ones.
+// Every score at least this far from zero is a win or loss
+const decisiveScore = winMax - float32(maxPly)
+
+// Score of a node where the player to move has already lost, ply moves from the root
+func lossScore(ply int) float32 {
+	return winMin + float32(ply)
+}
+
+func isDecisive(score float32) bool {
+	return score >= decisiveScore || score <= -decisiveScore
+}
+
+// The alpha of a zero window that only tests whether a score reaches beta.
+// Around the win scores a float32 is too coarse to hold beta-zeroWindowWidth, so it takes the next float below beta instead
+func zeroWindowAlpha(beta float32) float32 {
+	if alpha := beta - zeroWindowWidth; alpha < beta {
+		return alpha
+	}
+	return math.Nextafter32(beta, bestMin)
+}
+
+// Narrows the window to the scores still possible ply moves from the root.
+// The player to move can't lose any sooner than right here, and can't win any sooner than with their next move
+func mateDistanceWindow(ply int, alpha float32, beta float32) (float32, float32) {
+	if loss := lossScore(ply); alpha < loss {
+		alpha = loss
+	}
+	if win := -lossScore(ply + 1); beta > win {
+		beta = win
+	}
+	return alpha, beta
+}
+
+// MovesToWin returns how many of their own moves the player the score is for needs to capture the king.
+// For a loss it returns minus the moves the opponent needs. Scores that aren't wins or losses return 0
+func MovesToWin(score float32) int {
+	switch {
+	case score >= decisiveScore && score <= winMax:
+		plies := int(winMax - score)
+		return (plies + 1) / 2
+	case score <= -decisiveScore && score >= winMin:
+		plies := int(score - winMin)
+		return -plies / 2
+	}
+	return 0
+}
+
+// FormatScore writes wins and losses as "win in N" or "loss in N", counting moves of the winning side, and other scores as a number
+func FormatScore(score float32) string {
+	moves := MovesToWin(score)
+	switch {
+	case moves > 0:
+		return fmt.Sprintf("win in %d", moves)
+	case moves < 0:
+		return fmt.Sprintf("loss in %d", -moves)
+	}
+	return fmt.Sprintf("%.2f", score)
+}
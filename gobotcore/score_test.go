@@ -0,0 +1,62 @@
+package gobotcore
+
+import "testing"
+
+func TestScore_MovesToWin(t *testing.T) {
+	cases := []struct {
+		score    float32
+		moves    int
+		expected string
+	}{
+		{-lossScore(1), 1, "win in 1"},
+		{-lossScore(5), 3, "win in 3"},
+		{lossScore(2), -1, "loss in 1"},
+		{lossScore(6), -3, "loss in 3"},
+		{12.5, 0, "12.50"},
+		{bestMin, 0, "-9999999.00"},
+	}
+	for _, c := range cases {
+		if moves := MovesToWin(c.score); moves != c.moves {
+			t.Errorf("Score %f should be %d moves, got %d", c.score, c.moves, moves)
+		}
+		if str := FormatScore(c.score); str != c.expected {
+			t.Errorf("Score %f should format as %q, got %q", c.score, c.expected, str)
+		}
+	}
+}
+
+func TestScore_QuickestWin(t *testing.T) {
+	// The position of TestBoard_Minimax2. Gobot's bishop can take the king right away, so no deeper win should be preferred
+	board, player, err := ParsePosition("6/6/6/6/6/6/2B3/1r1k2 g")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var depth int8
+	for depth = 1; depth <= 5; depth++ {
+		best := board.Search(&player, SearchLimits{Depth: depth}, nil, nil)
+		expected := NewMoveFromString("C2D1")
+		if !best.Move().Equals(&expected) || MovesToWin(best.score) != 1 {
+			t.Errorf("Depth %d should win in 1 with C2D1, got %s with %s", depth, best.Move().ToString(), FormatScore(best.score))
+		}
+	}
+}
+
+func TestScore_MateDistanceWindow(t *testing.T) {
+	alpha, beta := mateDistanceWindow(3, bestMin, bestMax)
+	if alpha != lossScore(3) || beta != -lossScore(4) {
+		t.Errorf("The window at ply 3 should be from %f to %f, got %f to %f", lossScore(3), -lossScore(4), alpha, beta)
+	}
+	// A win in 1 already found at the root can't be beaten 3 plies down
+	if alpha, beta = mateDistanceWindow(3, -lossScore(1), bestMax); alpha < beta {
+		t.Error("A quicker win found elsewhere should close the window")
+	}
+}
+
+func TestScore_ZeroWindowAlpha(t *testing.T) {
+	// Near the win scores a float32 can't tell beta from beta minus zeroWindowWidth, which would leave an empty window
+	for _, beta := range []float32{0, 12.5, -lossScore(9), lossScore(9)} {
+		if alpha := zeroWindowAlpha(beta); alpha >= beta || alpha < beta-1 {
+			t.Errorf("The zero window below %f should be just under it, got %f", beta, alpha)
+		}
+	}
+}
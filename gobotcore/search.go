@@ -0,0 +1,410 @@
+package gobotcore
+
+import (
+	"math/rand"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Deepest iteration the search will start. Keeps the int8 depth from overflowing on tiny boards
+const MaxSearchDepth int8 = 64
+
+// A deterministic search is given this many nodes for every second it would have had. About what a single goroutine searches
+const deterministicNodesPerSecond = 300000
+
+// Iterations after the first search a window this far on either side of the last score.
+// A search that falls outside widens the window by aspirationGrowth on that side, until it is wider than maxAspirationWindow
+const (
+	aspirationWindow    float32 = 4
+	aspirationGrowth    float32 = 4
+	maxAspirationWindow float32 = 256
+)
+
+// Limits for a single search. A zero value means no limit for that field
+type SearchLimits struct {
+	Depth    int8          // Stop after this depth is completed
+	MoveTime time.Duration // Stop after this much time has passed
+	Nodes    int64         // Stop after about this many nodes have been searched
+	// Time left on the clock of the player to move, added to it after every move, and moves until the next time control.
+	// Without a MoveTime the search decides how long to think from these, see timeManager. MovesToGo 0 means the rest of the game
+	Clock     time.Duration
+	Increment time.Duration
+	MovesToGo int
+	// Iterative deepening starts at this depth instead of 1. Its result is used even if it is cut short
+	StartDepth int8
+	// Only these root moves are searched. Illegal ones are ignored, and if none are legal every move is searched
+	SearchMoves Moves
+}
+
+// Changes how the search plays, rather than how long it searches. The zero value plays the best move it can find
+type SearchOptions struct {
+	Personality Personality
+	// Pick randomly among the root moves that score within this much of the best move
+	Randomness float32
+	// Add random noise of up to this much to every evaluation, so the search misjudges positions
+	Mistakes float32
+	// Sort moves by Move weight only, like Gobot used to. For comparing node counts
+	NoMoveOrdering bool
+	// Turn off the pruning that skips or shortens moves that probably won't matter. See pruning.go
+	NoNullMove           bool
+	NoLateMoveReductions bool
+	NoFutility           bool
+	// Score the leaves as they are, instead of searching their captures first. See quiesce
+	NoQuiescence bool
+	// Find this many best root moves, each with its own score and line, instead of just the best one. See SearchLines
+	MultiPV int
+	// Search on a single goroutine, so the same position, limits and options always give the same result, down to the node count.
+	// Time limits become node limits at deterministicNodesPerSecond. Randomness and Mistakes draw from a generator seeded with Seed
+	Deterministic bool
+	Seed          int64
+	// Records the tree the search explored. Tracing also keeps the search on a single goroutine. See trace.go
+	Trace *Trace
+	// Scores positions with this network instead of the material and mobility. nil for the hand written evaluation. See network.go
+	Network *Network
+}
+
+// Sent to the info callback after every completed iteration of the search
+type SearchInfo struct {
+	Depth int8
+	Score float32
+	Nodes int64
+	Time  time.Duration
+	PV    Moves
+	// Every line the iteration found, best first, when the search looks for more than one. Lines[0] has the Score and PV above
+	Lines []ScoredMove
+}
+
+// State shared by every goroutine of a single search, so that separate searches don't interfere with each other
+type searchControl struct {
+	over     int32 // Set to 1 when the search has to stop. Accessed atomically
+	nodes    int64 // Accessed atomically
+	maxNodes int64 // Accessed atomically, since a deterministic ponder sets it when it is hit
+	options  SearchOptions
+	random   *rand.Rand // Only for deterministic searches, which never use it from more than one goroutine
+	ordering *moveOrdering
+	start    time.Time
+	done     chan struct{}
+
+	timeMu sync.Mutex   // Guards time and timer, which a ponder sets while its search runs
+	time   *timeManager // nil without a time limit
+	timer  *time.Timer
+
+	accumulators sync.Map // *Board to its *accumulator, only with a Network
+}
+
+func newSearchControl(board *Board, limits SearchLimits, options SearchOptions, stop <-chan struct{}) *searchControl {
+	variantLock.RLock()
+	control := &searchControl{
+		maxNodes: limits.Nodes,
+		options:  options,
+		ordering: &moveOrdering{},
+		start:    time.Now(),
+		done:     make(chan struct{}),
+	}
+	if options.Deterministic {
+		control.random = rand.New(rand.NewSource(options.Seed))
+	}
+
+	control.startClock(board, limits)
+
+	go func() {
+		select {
+		case <-stop:
+		case <-control.done:
+			return
+		}
+		atomic.StoreInt32(&control.over, 1)
+	}()
+	return control
+}
+
+// Starts the time limits of the search with its one deadline. Iterations are never restarted with a timer of their own.
+// Normally called when the search starts, but a ponder only calls it when the opponent plays the expected move.
+// A deterministic search can't look at the time, so it gets the nodes it could search in the planned time instead
+func (control *searchControl) startClock(board *Board, limits SearchLimits) {
+	manager := newTimeManager(limits, board, time.Now())
+	if manager == nil {
+		return
+	}
+	if control.options.Deterministic {
+		nodes := int64(manager.planned.Seconds() * deterministicNodesPerSecond)
+		if nodes < 1 {
+			nodes = 1
+		}
+		if limits.Nodes == 0 {
+			atomic.StoreInt64(&control.maxNodes, atomic.LoadInt64(&control.nodes)+nodes)
+		}
+		return
+	}
+	control.timeMu.Lock()
+	defer control.timeMu.Unlock()
+	control.time = manager
+	control.timer = time.AfterFunc(manager.maximum, func() {
+		atomic.StoreInt32(&control.over, 1)
+	})
+}
+
+func (control *searchControl) timeManager() *timeManager {
+	control.timeMu.Lock()
+	defer control.timeMu.Unlock()
+	return control.time
+}
+
+// Deterministic and traced searches don't spread the work over goroutines
+func (control *searchControl) singleThreaded() bool {
+	return control.options.Deterministic || control.options.Trace != nil
+}
+
+func (control *searchControl) isOver() bool {
+	return atomic.LoadInt32(&control.over) == 1
+}
+
+func (control *searchControl) countNode() {
+	maxNodes := atomic.LoadInt64(&control.maxNodes)
+	if nodes := atomic.AddInt64(&control.nodes, 1); maxNodes > 0 && nodes >= maxNodes {
+		atomic.StoreInt32(&control.over, 1)
+	}
+}
+
+// Scores the board for player with the personality and mistakes of this search
+func (control *searchControl) evaluate(board *Board, player *Player) float32 {
+	var score float32
+	if control.options.Network != nil {
+		score = control.networkScore(board, player)
+	} else {
+		score = board.GetWeightedScoreForPlayer(player)
+	}
+	options := &control.options
+	if options.Personality.CaptureBonus != 0 {
+		captures := board.countCaptures(*player) - board.countCaptures(*player.Opponent())
+		score += options.Personality.CaptureBonus * float32(captures)
+	}
+	if options.Personality.KingSafetyBonus != 0 {
+		guards := board.countKingGuards(player) - board.countKingGuards(player.Opponent())
+		score += options.Personality.KingSafetyBonus * float32(guards)
+	}
+	if options.Mistakes != 0 {
+		score += (control.randomFloat()*2 - 1) * options.Mistakes
+	}
+	return score
+}
+
+// Releases the timer and its goroutine, and lets the variant change again. Must be called once the search is done
+func (control *searchControl) finish() {
+	control.timeMu.Lock()
+	if control.timer != nil {
+		control.timer.Stop()
+	}
+	control.timeMu.Unlock()
+	close(control.done)
+	variantLock.RUnlock()
+}
+
+// Search finds the best move for player with iterative deepening, starting at limits.StartDepth or 1.
+// The search ends when a limit is hit or when stop is closed, or when the next iteration wouldn't finish in time. stop and info may be nil.
+// The result of the deepest completed iteration is returned. One that was cut short is thrown away, unless it is the only one
+func (board *Board) Search(player *Player, limits SearchLimits, stop <-chan struct{}, info func(SearchInfo)) ScoredMove {
+	return board.SearchWithOptions(player, limits, SearchOptions{}, stop, info)
+}
+
+// Same as Search, but plays with the given options
+func (board *Board) SearchWithOptions(player *Player, limits SearchLimits, options SearchOptions, stop <-chan struct{}, info func(SearchInfo)) ScoredMove {
+	control := newSearchControl(board, limits, options, stop)
+	best, _ := board.deepen(player, limits, control, info)
+	return best
+}
+
+// SearchLines finds the options.MultiPV best root moves, each with its own score and line, for studying a position.
+// Returns the lines of the deepest completed iteration, best first. The result is empty if player has no move
+func (board *Board) SearchLines(player *Player, limits SearchLimits, options SearchOptions, stop <-chan struct{}, info func(SearchInfo)) []ScoredMove {
+	control := newSearchControl(board, limits, options, stop)
+	_, lines := board.deepen(player, limits, control, info)
+	return lines
+}
+
+// The iterative deepening of Search. Returns the move to play and every line of the deepest completed iteration
+func (board *Board) deepen(player *Player, limits SearchLimits, control *searchControl, info func(SearchInfo)) (ScoredMove, []ScoredMove) {
+	defer control.finish()
+
+	startDepth := limits.StartDepth
+	if startDepth < 1 {
+		startDepth = 1
+	}
+	if limits.Depth > 0 && startDepth > limits.Depth {
+		startDepth = limits.Depth
+	}
+
+	moves := board.rootMoves(player, limits.SearchMoves)
+	var best ScoredMove
+	var lines, rootMoves []ScoredMove
+	for depth := startDepth; depth <= MaxSearchDepth; depth++ {
+		iterationStart := time.Now()
+		curLines, scored := board.searchLines(player, moves, depth, lines, depth == startDepth, control)
+		if control.isOver() && depth > startDepth {
+			break
+		}
+		cur := curLines[0]
+		bestMoveChanged := depth > startDepth && !cur.move.Equals(&best.move)
+		best, lines, rootMoves = cur, curLines, scored
+		if len(best.pv) == 0 {
+			lines = nil
+		}
+
+		if info != nil {
+			info(SearchInfo{
+				Depth: depth,
+				Score: best.score,
+				Nodes: atomic.LoadInt64(&control.nodes),
+				Time:  time.Since(control.start),
+				PV:    best.pv,
+				Lines: lines,
+			})
+		}
+		if control.isOver() || depth == limits.Depth || len(best.pv) == 0 {
+			break
+		}
+		if manager := control.timeManager(); manager != nil {
+			manager.iterationDone(time.Since(iterationStart), bestMoveChanged)
+			if !manager.canStartIteration() {
+				break
+			}
+		}
+	}
+
+	if randomness := control.options.Randomness; randomness > 0 {
+		return control.pickNearBest(best, rootMoves, randomness), lines
+	}
+	return best, lines
+}
+
+// The legal moves of player, limited to searchMoves unless none of them is legal
+func (board *Board) rootMoves(player *Player, searchMoves Moves) Moves {
+	legal := board.LegalMovesForPlayer(*player)
+	moves := make(Moves, 0, len(legal))
+	for _, move := range legal {
+		if move.IsContainedIn(&searchMoves) {
+			moves = append(moves, move)
+		}
+	}
+	if len(moves) == 0 {
+		return legal
+	}
+	return moves
+}
+
+/* Searches one iteration of every line. Each line after the first searches the root without the moves of the lines
+ * found before it, so it finds the next best move. last are the lines of the iteration before, which give each line its window.
+ * Returns at least one line, with no move if there are no moves. Also returns the scores of every root move from the search of the first line
+ */
+func (board *Board) searchLines(player *Player, moves Moves, depth int8, last []ScoredMove, first bool, control *searchControl) ([]ScoredMove, []ScoredMove) {
+	numLines := control.options.MultiPV
+	if numLines < 1 {
+		numLines = 1
+	}
+	lines := make([]ScoredMove, 0, numLines)
+	found := make(Moves, 0, numLines)
+	var rootMoves []ScoredMove
+	// Only the first line searches every root move, so the later lines aren't traced over it
+	trace := control.options.Trace
+	for i := 0; i < numLines; i++ {
+		if i == 1 {
+			control.options.Trace = nil
+		}
+		remaining := make(Moves, 0, len(moves))
+		for _, move := range moves {
+			if !move.IsContainedIn(&found) {
+				remaining = append(remaining, move)
+			}
+		}
+		if i > 0 && len(remaining) == 0 {
+			break
+		}
+
+		var previous ScoredMove
+		if i < len(last) {
+			previous = last[i]
+		}
+		control.ordering.pv = previous.pv
+		cur, scored := board.aspirationSearch(player, remaining, depth, previous, first, control)
+		if i == 0 {
+			rootMoves = scored
+		} else if control.isOver() {
+			break // A line that was cut short can't be compared with the others
+		}
+		lines = append(lines, cur)
+		found = append(found, cur.move)
+		if len(cur.pv) == 0 || control.isOver() {
+			break
+		}
+	}
+	control.options.Trace = trace
+
+	sort.SliceStable(lines, func(i, j int) bool {
+		return lines[i].score > lines[j].score
+	})
+	return lines, rootMoves
+}
+
+/* Searches one iteration with a window around last's score, which cuts more of the tree when the score barely changes.
+ * If the score falls outside the window, the iteration is searched again with the window widened on that side.
+ * The first iteration has no score to go on, and with Randomness every move near the best needs an exact score, so they get the full window
+ */
+func (board *Board) aspirationSearch(player *Player, moves Moves, depth int8, last ScoredMove, first bool, control *searchControl) (ScoredMove, []ScoredMove) {
+	alpha, beta := bestMin, bestMax
+	delta := aspirationWindow
+	if !first && control.options.Randomness == 0 && !isDecisive(last.score) {
+		alpha, beta = last.score-delta, last.score+delta
+	}
+
+	for {
+		control.options.Trace.enterRoot(depth, alpha, beta)
+		best, scored := board.minimaxRoot(player, moves, depth, alpha, beta, control)
+		control.options.Trace.exitRoot(best.score, control.isOver())
+		if control.isOver() {
+			return best, scored
+		}
+		switch {
+		case best.score <= alpha && alpha > bestMin:
+			delta *= aspirationGrowth
+			alpha = last.score - delta
+		case best.score >= beta && beta < bestMax:
+			delta *= aspirationGrowth
+			beta = last.score + delta
+		default:
+			return best, scored
+		}
+		if delta > maxAspirationWindow {
+			alpha, beta = bestMin, bestMax
+		}
+	}
+}
+
+// Picks one of the root moves that scored within margin of the best one
+func (control *searchControl) pickNearBest(best ScoredMove, rootMoves []ScoredMove, margin float32) ScoredMove {
+	var candidates []ScoredMove
+	for _, scored := range rootMoves {
+		if scored.score >= best.score-margin {
+			candidates = append(candidates, scored)
+		}
+	}
+	if len(candidates) == 0 {
+		return best
+	}
+	return candidates[control.randomIntn(len(candidates))]
+}
+
+func (control *searchControl) randomFloat() float32 {
+	if control.random != nil {
+		return control.random.Float32()
+	}
+	return rand.Float32()
+}
+
+func (control *searchControl) randomIntn(n int) int {
+	if control.random != nil {
+		return control.random.Intn(n)
+	}
+	return rand.Intn(n)
+}
@@ -0,0 +1,156 @@
+package gobotcore
+
+import (
+	"testing"
+	"time"
+)
+
+var noPruning = SearchOptions{NoNullMove: true, NoLateMoveReductions: true, NoFutility: true, NoQuiescence: true}
+
+var searchPositions = []string{
+	StartPosition,
+	"1K4/NBR1BN/2PP2/6/3p2/2p3/nbrrbn/4k1 g",
+	"1K1r2/NBRR2/2PP2/6/6/2pp2/nb1r2/4k1 g",
+	"K5/6/6/6/1P4/2p3/n1b3/1R2k1 h",
+}
+
+// Plain minimax without any pruning. Scores are from player's point of view, like Negamax
+func minimaxScore(board *Board, player Player, depth int8, ply int, numParentMoves int, control *searchControl) float32 {
+	moves := board.LegalMovesForPlayer(player)
+	if board.IsGameOverForPlayer(&player, &moves) {
+		return lossScore(ply)
+	}
+	if depth == 1 {
+		return control.staticScore(board, &player, moves, numParentMoves)
+	}
+	best := bestMin
+	for _, move := range moves {
+		taken := *board.MakeMoveAndGetTakenPiece(&move)
+		if score := -minimaxScore(board, *player.Opponent(), depth-1, ply+1, len(moves), control); score > best {
+			best = score
+		}
+		board.RetractMove(&move, taken)
+	}
+	return best
+}
+
+func TestSearch_MatchesMinimax(t *testing.T) {
+	control := newSearchControl(nil, SearchLimits{}, SearchOptions{}, nil)
+	defer control.finish()
+	for _, position := range searchPositions {
+		board, player, _ := ParsePosition(position)
+		var depth int8
+		for depth = 1; depth <= 4; depth++ {
+			// Root moves are searched with the same depth as the root, see minimaxRoot
+			moves := board.LegalMovesForPlayer(player)
+			expected := bestMin
+			for _, move := range moves {
+				taken := *board.MakeMoveAndGetTakenPiece(&move)
+				if score := -minimaxScore(&board, *player.Opponent(), depth, 1, len(moves), control); score > expected {
+					expected = score
+				}
+				board.RetractMove(&move, taken)
+			}
+
+			best := board.SearchWithOptions(&player, SearchLimits{Depth: depth}, noPruning, nil, nil)
+			if best.score != expected {
+				t.Errorf("Depth %d of %s should score %f like minimax, got %f", depth, position, expected, best.score)
+			}
+		}
+	}
+}
+
+func TestSearch_AspirationResearch(t *testing.T) {
+	for _, position := range searchPositions {
+		board, player, _ := ParsePosition(position)
+		control := newSearchControl(nil, SearchLimits{}, noPruning, nil)
+		moves := board.LegalMovesForPlayer(player)
+		full, _ := board.aspirationSearch(&player, moves, 5, ScoredMove{}, true, control)
+		// A last score far below and far above the real one makes the window fail high and fail low
+		low, _ := board.aspirationSearch(&player, moves, 5, ScoredMove{score: full.score - 50}, false, control)
+		high, _ := board.aspirationSearch(&player, moves, 5, ScoredMove{score: full.score + 50}, false, control)
+		control.finish()
+		if low.score != full.score || high.score != full.score {
+			t.Errorf("Windows that miss should be widened until they find score %f in %s, got %f and %f", full.score, position, low.score, high.score)
+		}
+	}
+}
+
+func TestSearch_MultiPV(t *testing.T) {
+	for _, position := range searchPositions {
+		board, player, _ := ParsePosition(position)
+		options := noPruning
+		options.MultiPV = 3
+		lines := board.SearchLines(&player, SearchLimits{Depth: 4}, options, nil, nil)
+		if len(lines) != 3 {
+			t.Fatalf("Should find 3 lines in %s, got %d", position, len(lines))
+		}
+		for i, line := range lines {
+			if i > 0 && (line.score > lines[i-1].score || line.move.Equals(&lines[i-1].move)) {
+				t.Errorf("Lines of %s should be different moves, best first, got %s after %s", position, line.move.ToString(), lines[i-1].move.ToString())
+			}
+			// Each line scores the same as a search of only its move
+			only := board.SearchWithOptions(&player, SearchLimits{Depth: 4, SearchMoves: Moves{line.move}}, noPruning, nil, nil)
+			if !only.move.Equals(&line.move) || only.score != line.score {
+				t.Errorf("Line %s of %s should score %f, got %f", line.move.ToString(), position, only.score, line.score)
+			}
+		}
+		if best := board.SearchWithOptions(&player, SearchLimits{Depth: 4}, noPruning, nil, nil); best.score != lines[0].score {
+			t.Errorf("The first line of %s should score %f like the best move, got %f", position, best.score, lines[0].score)
+		}
+	}
+}
+
+func TestSearch_SearchMoves(t *testing.T) {
+	board := NewDefaultBoard()
+	human := Player(HUMAN)
+	legal := board.LegalMovesForPlayer(human)
+	searchMoves := Moves{legal[len(legal)-1], legal[len(legal)-2], NewMoveFromString("A1A8")}
+	best := board.Search(&human, SearchLimits{Depth: 3, SearchMoves: searchMoves}, nil, nil)
+	if !best.move.IsContainedIn(&searchMoves) {
+		t.Error("Should only search the given moves, got", best.move.ToString())
+	}
+
+	illegal := Moves{NewMoveFromString("A1A8")}
+	if best = board.Search(&human, SearchLimits{Depth: 1, SearchMoves: illegal}, nil, nil); len(best.pv) == 0 {
+		t.Error("Should search every move when none of the given ones is legal")
+	}
+}
+
+func TestSearch_Deterministic(t *testing.T) {
+	options := SearchOptions{Deterministic: true, Seed: 7, Randomness: 3, Mistakes: 1}
+	limits := SearchLimits{Nodes: 20000}
+	for _, position := range searchPositions {
+		var first SearchInfo
+		var firstMove ScoredMove
+		for run := 0; run < 3; run++ {
+			board, player, _ := ParsePosition(position)
+			var last SearchInfo
+			best := board.SearchWithOptions(&player, limits, options, nil, func(info SearchInfo) {
+				last = info
+			})
+			if run == 0 {
+				first, firstMove = last, best
+				continue
+			}
+			if last.Depth != first.Depth || last.Score != first.Score || last.Nodes != first.Nodes || last.PV.ToString() != first.PV.ToString() ||
+				!best.move.Equals(&firstMove.move) {
+				t.Errorf("Run %d of %s should repeat depth %d score %f nodes %d pv %s move %s, got depth %d score %f nodes %d pv %s move %s",
+					run, position, first.Depth, first.Score, first.Nodes, first.PV.ToString(), firstMove.move.ToString(),
+					last.Depth, last.Score, last.Nodes, last.PV.ToString(), best.move.ToString())
+			}
+		}
+	}
+}
+
+func TestSearch_DeterministicMoveTime(t *testing.T) {
+	board := NewDefaultBoard()
+	human := Player(HUMAN)
+	var last SearchInfo
+	board.SearchWithOptions(&human, SearchLimits{MoveTime: 50 * time.Millisecond}, SearchOptions{Deterministic: true}, nil, func(info SearchInfo) {
+		last = info
+	})
+	if last.Nodes > deterministicNodesPerSecond/20+int64(maxPly) {
+		t.Error("The move time should become a node limit, searched", last.Nodes)
+	}
+}
@@ -0,0 +1,79 @@
+package gobotcore
+
+/* Static exchange evaluation. Works out what a capture wins once both players have made every capture on its square
+ * they want to, always capturing with their least valuable piece first. A player can stop capturing whenever going on
+ * would lose them material. In Morph the capturing piece morphs when it lands, so after every capture the attackers
+ * of the square are found again on the changed board. That also finds the pieces a capture uncovers behind it
+ */
+func (board *Board) StaticExchange(move Move) float32 {
+	exchange := *board
+	target := move.to
+	attacker := exchange.PieceAt(&move.from)
+	player := ownerOf(attacker)
+
+	// gains[i] is what the player making capture i has won so far, if the other player stops after it
+	gains := make([]float32, 0, 8)
+	victim := exchange.PieceAt(&target)
+	gains = append(gains, victim.Weight())
+	exchange.MakeMoveAndGetTakenPiece(&move)
+
+	for !victim.IsKing() {
+		player = *player.Opponent()
+		recapture, ok := exchange.leastValuableCapture(target, player)
+		if !ok {
+			break
+		}
+		victim = exchange.PieceAt(&target)
+		gains = append(gains, victim.Weight()-gains[len(gains)-1])
+		exchange.MakeMoveAndGetTakenPiece(&recapture)
+	}
+
+	// Going backwards, each player only makes their capture if it gains more than stopping before it
+	for i := len(gains) - 1; i > 0; i-- {
+		if -gains[i-1] > gains[i] {
+			gains[i] = -gains[i-1]
+		}
+		gains[i-1] = -gains[i]
+	}
+	return gains[0]
+}
+
+// True if the capture might lose material. A piece taking one at least as heavy can never lose more than it took,
+// since morphing never changes a piece's weight
+func (board *Board) canLoseExchange(move Move) bool {
+	attacker := board.PieceAt(&move.from)
+	victim := board.PieceAt(&move.to)
+	return attacker.Weight() > victim.Weight()
+}
+
+// The capture of the piece on target with player's least valuable piece
+func (board *Board) leastValuableCapture(target Location, player Player) (Move, bool) {
+	var best Move
+	bestWeight := float32(0)
+	found := false
+	var row, col int8
+	for row = 0; row < boardRows; row++ {
+		for col = 0; col < boardCols; col++ {
+			location := Location{row: row, col: col}
+			piece := board.PieceAt(&location)
+			if piece.IsEmpty() || !piece.IsOwnedBy(&player) || (found && piece.Weight() >= bestWeight) {
+				continue
+			}
+			for _, move := range board.FindMovesForPlayersPieceAtLocation(piece, player, location) {
+				if move.to.Equals(&target) {
+					best, bestWeight, found = move, piece.Weight(), true
+					break
+				}
+			}
+		}
+	}
+	return best, found
+}
+
+func ownerOf(piece Piece) Player {
+	gobot := Player(GOBOT)
+	if piece.IsOwnedBy(&gobot) {
+		return GOBOT
+	}
+	return HUMAN
+}
@@ -0,0 +1,149 @@
+package gobotcore
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+)
+
+// Builds a board from its eight rows, top row first, in the format of NewBoardFromString
+func boardFromRows(rows ...string) Board {
+	var buffer bytes.Buffer
+	for i, row := range rows {
+		buffer.WriteString(fmt.Sprintf("%d   %s\n", 8-i, row))
+	}
+	buffer.WriteString("\n")
+	buffer.WriteString("    A B C D E F")
+	return NewBoardFromString(buffer.String())
+}
+
+func TestStaticExchange(t *testing.T) {
+	tests := []struct {
+		name     string
+		board    Board
+		move     string
+		expected float32
+	}{
+		{
+			name: "undefended pawn",
+			board: boardFromRows(
+				"K - - - - -",
+				"- - - - - -",
+				"- B - - - -",
+				"- - - - - -",
+				"- - - p - -",
+				"- - - - - -",
+				"- - - - - -",
+				"- - - - - k"),
+			move:     "B6D4",
+			expected: 1,
+		},
+		{
+			name: "pawn defended by a rook",
+			board: boardFromRows(
+				"K - - - - -",
+				"- - - - - -",
+				"- B - - - -",
+				"- - - - - -",
+				"- - - p - -",
+				"- - - - - -",
+				"- - - - - -",
+				"- - - r - k"),
+			move:     "B6D4",
+			expected: -5,
+		},
+		{
+			// The second rook only reaches the pawn once the first one has recaptured
+			name: "rooks behind each other",
+			board: boardFromRows(
+				"K - - - - -",
+				"- - - - - -",
+				"- B - - - B",
+				"- - - - - -",
+				"- - - p - -",
+				"- - - - - -",
+				"- - - r - -",
+				"- - - r - k"),
+			move:     "B6D4",
+			expected: -5,
+		},
+		{
+			name: "king recaptures",
+			board: boardFromRows(
+				"K - - - - -",
+				"- - - - - -",
+				"- B - - - -",
+				"- - - - - -",
+				"- - - p k -",
+				"- - - - - -",
+				"- - - - - -",
+				"- - - - - -"),
+			move:     "B6D4",
+			expected: -5,
+		},
+		{
+			// Recapturing with the king would let the rook take it
+			name: "king can't recapture",
+			board: boardFromRows(
+				"K - - R - -",
+				"- - - - - -",
+				"- B - - - -",
+				"- - - - - -",
+				"- - - p k -",
+				"- - - - - -",
+				"- - - - - -",
+				"- - - - - -"),
+			move:     "B6D4",
+			expected: 1,
+		},
+		{
+			name: "taking the king",
+			board: boardFromRows(
+				"K - - - - -",
+				"- - - - - -",
+				"- B - - - -",
+				"- - - - - -",
+				"- - - k - -",
+				"- - - - - -",
+				"- - - - - -",
+				"- - - r - -"),
+			move:     "B6D4",
+			expected: 1000,
+		},
+	}
+	for _, test := range tests {
+		move := NewMoveFromString(test.move)
+		before := test.board
+		if exchange := test.board.StaticExchange(move); exchange != test.expected {
+			t.Errorf("%s: %s should win %v, got %v", test.name, test.move, test.expected, exchange)
+		}
+		if test.board != before {
+			t.Errorf("%s: the exchange changed the board", test.name)
+		}
+	}
+}
+
+func TestQuiesce_AvoidsLosingCapture(t *testing.T) {
+	SetDebug(false)
+	// At depth 1 taking the pawn looks like it wins it, until the rook recaptures
+	board := boardFromRows(
+		"K - - - - -",
+		"- - - - - -",
+		"- B - - - -",
+		"- - - - - -",
+		"- - - p - -",
+		"- - - - - -",
+		"- - - - - -",
+		"- - - r - k")
+	player := Player(GOBOT)
+	capture := NewMoveFromString("B6D4")
+
+	quiet := board.SearchWithOptions(&player, SearchLimits{Depth: 1}, SearchOptions{Deterministic: true, NoQuiescence: true}, nil, nil)
+	if !quiet.Move().Equals(&capture) {
+		t.Skip("Without quiescence the search already avoids the capture, so this position tests nothing")
+	}
+	move := board.SearchWithOptions(&player, SearchLimits{Depth: 1}, deterministic, nil, nil)
+	if move.Move().Equals(&capture) {
+		t.Error("Quiescence should see the rook recapture on D4, got " + move.Move().ToString())
+	}
+}
@@ -0,0 +1,135 @@
+package gobotcore
+
+import "time"
+
+const (
+	// Never plan to use the whole clock
+	timeSafetyMargin = 50 * time.Millisecond
+	// Moves we expect are left in the game with every piece still on the board, and with only kings and pawns left
+	openingMovesLeft = 40
+	endgameMovesLeft = 15
+	// The search is stopped at this many times the planned time, even in the middle of an iteration
+	maxTimeFactor = 4
+	// Each iteration is assumed to take this many times as long as the one before, until two iterations have been timed
+	defaultBranchingFactor = 4.0
+	minBranchingFactor     = 1.5
+	maxBranchingFactor     = 8.0
+	// Iterations this short are too noisy to time
+	minTimedIteration = time.Millisecond
+)
+
+/* Decides how long a single search thinks. It plans an amount of time to use from the clock and the game phase,
+ * and a hard deadline the search is stopped at. Iterative deepening asks it before every iteration whether
+ * the next one can still finish in the planned time, so that it doesn't start an iteration it will have to throw away.
+ * When the best move changes between iterations the position is harder than it looked, so the plan gets longer
+ */
+type timeManager struct {
+	start    time.Time
+	planned  time.Duration // Time we plan to use. Grows when the best move changes
+	extended time.Duration // Added to planned for every change of the best move
+	maximum  time.Duration // The hard deadline. The search is stopped here
+
+	lastIteration time.Duration
+	branching     float64
+}
+
+// Returns nil if the limits don't limit time
+func newTimeManager(limits SearchLimits, board *Board, start time.Time) *timeManager {
+	manager := &timeManager{start: start, branching: defaultBranchingFactor}
+	switch {
+	case limits.MoveTime > 0:
+		manager.planned = limits.MoveTime
+		manager.maximum = limits.MoveTime
+	case limits.Clock > 0:
+		manager.planned, manager.maximum = allocateTime(limits, board)
+	default:
+		return nil
+	}
+	manager.extended = manager.planned / 2
+	return manager
+}
+
+// Splits the clock over the moves we expect are left, plus most of the increment.
+// Returns the planned time and the hard deadline
+func allocateTime(limits SearchLimits, board *Board) (time.Duration, time.Duration) {
+	movesLeft := board.estimateMovesLeft()
+	if limits.MovesToGo > 0 && limits.MovesToGo < movesLeft {
+		movesLeft = limits.MovesToGo
+	}
+	available := limits.Clock - timeSafetyMargin
+	if available < time.Millisecond {
+		available = time.Millisecond
+	}
+
+	planned := limits.Clock/time.Duration(movesLeft) + limits.Increment*3/4
+	maximum := planned * maxTimeFactor
+	// Never bet more than a third of the clock on a single move
+	if third := available / 3; maximum > third && limits.MovesToGo != 1 {
+		maximum = third
+	}
+	if maximum > available {
+		maximum = available
+	}
+	if planned > maximum {
+		planned = maximum
+	}
+	return planned, maximum
+}
+
+// The game phase decides how many moves are left. The more pieces other than pawns and kings are still on the board, the longer the game will go on.
+// A variant that starts with only pawns and kings is an endgame from the first move
+func (board *Board) estimateMovesLeft() int {
+	if startOfficers == 0 {
+		return endgameMovesLeft
+	}
+	pieces := board.countOfficers()
+	if pieces > startOfficers {
+		pieces = startOfficers
+	}
+	return endgameMovesLeft + (openingMovesLeft-endgameMovesLeft)*pieces/startOfficers
+}
+
+func (board *Board) countOfficers() int {
+	officers := 0
+	var row, col int8
+	for row = 0; row < boardRows; row++ {
+		for col = 0; col < boardCols; col++ {
+			location := Location{row: row, col: col}
+			if isOfficer(board.PieceAt(&location)) {
+				officers++
+			}
+		}
+	}
+	return officers
+}
+
+func isOfficer(piece Piece) bool {
+	return piece.Weight() > 1 && !piece.IsKing()
+}
+
+// Called after every completed iteration with how long it took and whether it changed the best move
+func (manager *timeManager) iterationDone(duration time.Duration, bestMoveChanged bool) {
+	if manager.lastIteration >= minTimedIteration {
+		branching := float64(duration) / float64(manager.lastIteration)
+		if branching < minBranchingFactor {
+			branching = minBranchingFactor
+		} else if branching > maxBranchingFactor {
+			branching = maxBranchingFactor
+		}
+		manager.branching = branching
+	}
+	manager.lastIteration = duration
+
+	if bestMoveChanged {
+		manager.planned += manager.extended
+		if manager.planned > manager.maximum {
+			manager.planned = manager.maximum
+		}
+	}
+}
+
+// True if the next iteration is expected to finish within the planned time
+func (manager *timeManager) canStartIteration() bool {
+	next := time.Duration(float64(manager.lastIteration) * manager.branching)
+	return time.Since(manager.start)+next <= manager.planned
+}
@@ -0,0 +1,91 @@
+package gobotcore
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTimeManager_Allocate(t *testing.T) {
+	opening := NewDefaultBoard()
+	endgame, _, _ := ParsePosition("1K4/2P3/6/6/6/6/3p2/4k1 g")
+	limits := SearchLimits{Clock: time.Minute}
+
+	planned, maximum := allocateTime(limits, &opening)
+	if planned != time.Minute/openingMovesLeft {
+		t.Error("The opening should plan for", openingMovesLeft, "moves, got", planned)
+	}
+	if maximum != planned*maxTimeFactor {
+		t.Error("The deadline should be", maxTimeFactor, "times the plan, got", maximum)
+	}
+	if endgamePlanned, _ := allocateTime(limits, &endgame); endgamePlanned <= planned {
+		t.Error("The endgame should get more time a move than the opening, got", endgamePlanned)
+	}
+
+	limits.MovesToGo = 2
+	limits.Increment = time.Second
+	planned, maximum = allocateTime(limits, &opening)
+	if maximum > (limits.Clock-timeSafetyMargin)/3 {
+		t.Error("A move should never get more than a third of the clock, got", maximum)
+	}
+	if planned > maximum {
+		t.Error("The plan should fit before the deadline, got", planned, maximum)
+	}
+
+	limits = SearchLimits{Clock: 20 * time.Millisecond}
+	if _, maximum = allocateTime(limits, &opening); maximum > limits.Clock {
+		t.Error("Should never use more than the clock, got", maximum)
+	}
+}
+
+func TestTimeManager_PawnsOnlyVariant(t *testing.T) {
+	defer SetVariant(Variants[0])
+	if err := SetVariant(Variant{Name: "pawns", Cols: 5, Rows: 6, Start: "1K3/5/ppppp/PPPPP/5/3k1 h"}); err != nil {
+		t.Fatal(err)
+	}
+	board := NewDefaultBoard()
+	if movesLeft := board.estimateMovesLeft(); movesLeft != endgameMovesLeft {
+		t.Error("A start with only pawns and kings should be an endgame, got", movesLeft)
+	}
+	player := Player(HUMAN)
+	best := board.Search(&player, SearchLimits{Clock: 200 * time.Millisecond}, nil, nil)
+	if !board.IsValidMoveForPlayer(best.Move(), player) {
+		t.Error("A timed search should find a legal move, got " + best.Move().ToString())
+	}
+}
+
+func TestTimeManager_Iterations(t *testing.T) {
+	// As if the two iterations below have already taken 400ms
+	manager := &timeManager{start: time.Now().Add(-400 * time.Millisecond), planned: time.Second, extended: time.Second / 2, maximum: 4 * time.Second, branching: defaultBranchingFactor}
+	manager.iterationDone(100*time.Millisecond, false)
+	if !manager.canStartIteration() {
+		t.Error("An iteration of about 400ms should still fit in the second")
+	}
+	manager.iterationDone(300*time.Millisecond, false)
+	if manager.branching != 3 {
+		t.Error("The branching factor should come from the last two iterations, got", manager.branching)
+	}
+	if manager.canStartIteration() {
+		t.Error("An iteration of about 900ms shouldn't start with 1s planned")
+	}
+	manager.iterationDone(300*time.Millisecond, true)
+	if manager.planned != 1500*time.Millisecond {
+		t.Error("A new best move should extend the plan, got", manager.planned)
+	}
+}
+
+func TestSearch_Clock(t *testing.T) {
+	board := NewDefaultBoard()
+	human := Player(HUMAN)
+	limits := SearchLimits{Clock: 2 * time.Second}
+	var last SearchInfo
+	start := time.Now()
+	best := board.Search(&human, limits, nil, func(info SearchInfo) {
+		last = info
+	})
+	if elapsed := time.Since(start); elapsed > (limits.Clock-timeSafetyMargin)/3+100*time.Millisecond {
+		t.Error("Should stop at the deadline, took", elapsed)
+	}
+	if last.Depth == 0 || !best.Move().Equals(&last.PV[0]) {
+		t.Error("Should return the move of the deepest completed iteration", last.Depth, "got", best.Move().ToString())
+	}
+}
@@ -0,0 +1,195 @@
+package gobotcore
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+/* Trace records the tree a search explored, for finding out why it picked a move. Pass it in SearchOptions.Trace.
+ * Every Negamax call becomes a node with its window, score and whether it cut off. A node is searched again
+ * when a zero window search beats alpha, so the same move can show up more than once under its parent.
+ * A traced search runs on a single goroutine, so the nodes are in the order they were searched.
+ * Only the last search of the root is kept: the last iteration, or the last window of its aspiration search.
+ * With MultiPV only the first line is traced, since the lines after it leave out the moves already found
+ */
+type Trace struct {
+	MaxPly   int // Nodes further than this from the root aren't recorded. 0 records every ply
+	MaxNodes int // Stop recording after this many nodes. 0 records every node
+
+	root      *TraceNode // The last search of the root that wasn't stopped
+	current   *TraceNode
+	stack     []traceFrame
+	nodes     int
+	truncated bool
+}
+
+// A node being searched
+type traceFrame struct {
+	node *TraceNode // nil if the node isn't recorded
+	move string     // The move the node is searching now, which leads to the next child entered
+}
+
+// A node of the tree. Like in Negamax, the window and score are from the point of view of the player to move at the node.
+// Moves are written like Move.ToString, and a null move as "null". "verify" is the shallower search of the node itself that confirms a null-move cutoff
+type TraceNode struct {
+	Move     string       `json:"move,omitempty"`
+	Depth    int8         `json:"depth"`
+	Ply      int          `json:"ply"`
+	Alpha    float32      `json:"alpha"`
+	Beta     float32      `json:"beta"`
+	Score    float32      `json:"score"`
+	Cutoff   bool         `json:"cutoff,omitempty"`  // The score reached beta, so the parent's search of this node was cut short
+	Stopped  bool         `json:"stopped,omitempty"` // The search ran out of time or nodes here
+	Children []*TraceNode `json:"children,omitempty"`
+}
+
+func NewTrace(maxPly int, maxNodes int) *Trace {
+	return &Trace{MaxPly: maxPly, MaxNodes: maxNodes}
+}
+
+// Root returns the tree of the last search of the root, or nil if nothing was traced
+func (trace *Trace) Root() *TraceNode {
+	if trace.root != nil {
+		return trace.root
+	}
+	return trace.current
+}
+
+// Truncated tells whether MaxNodes cut the tree short
+func (trace *Trace) Truncated() bool {
+	return trace.truncated
+}
+
+// Every method that records is safe to call on a nil trace, so the search doesn't have to check.
+// setMove is called by the node on top before searching a child
+func (trace *Trace) setMove(move Move) {
+	if trace != nil {
+		trace.setLabel(move.ToString())
+	}
+}
+
+// For the children that don't come from a move
+func (trace *Trace) setLabel(label string) {
+	if trace != nil && len(trace.stack) > 0 {
+		trace.stack[len(trace.stack)-1].move = label
+	}
+}
+
+func (trace *Trace) enterRoot(depth int8, alpha float32, beta float32) {
+	if trace == nil {
+		return
+	}
+	trace.stack = trace.stack[:0]
+	trace.nodes = 0
+	trace.truncated = false
+	trace.enter(depth, 0, alpha, beta)
+	trace.current = trace.stack[0].node
+}
+
+func (trace *Trace) exitRoot(score float32, stopped bool) {
+	if trace == nil {
+		return
+	}
+	trace.exit(score, stopped)
+	if !stopped {
+		trace.root = trace.current
+	}
+}
+
+func (trace *Trace) enter(depth int8, ply int, alpha float32, beta float32) {
+	if trace == nil {
+		return
+	}
+	var parent *TraceNode
+	move := ""
+	if len(trace.stack) > 0 {
+		parent = trace.stack[len(trace.stack)-1].node
+		move = trace.stack[len(trace.stack)-1].move
+	}
+	record := ply == 0 || parent != nil
+	if record && trace.MaxPly > 0 && ply > trace.MaxPly {
+		record = false
+	}
+	if record && trace.MaxNodes > 0 && trace.nodes >= trace.MaxNodes {
+		record = false
+		trace.truncated = true
+	}
+
+	var node *TraceNode
+	if record {
+		node = &TraceNode{Move: move, Depth: depth, Ply: ply, Alpha: alpha, Beta: beta}
+		trace.nodes++
+		if parent != nil {
+			parent.Children = append(parent.Children, node)
+		}
+	}
+	trace.stack = append(trace.stack, traceFrame{node: node})
+}
+
+func (trace *Trace) exit(score float32, stopped bool) {
+	if trace == nil || len(trace.stack) == 0 {
+		return
+	}
+	node := trace.stack[len(trace.stack)-1].node
+	trace.stack = trace.stack[:len(trace.stack)-1]
+	if node != nil {
+		node.Score = score
+		node.Cutoff = score >= node.Beta
+		node.Stopped = stopped
+	}
+}
+
+// WriteJSON writes the tree as nested JSON objects, see TraceNode
+func (trace *Trace) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(trace.Root())
+}
+
+// WriteDOT writes the tree for Graphviz, e.g. dot -Tsvg trace.dot > trace.svg.
+// Nodes that cut off are red, and nodes where the search was stopped are dashed
+func (trace *Trace) WriteDOT(w io.Writer) error {
+	var builder strings.Builder
+	builder.WriteString("digraph search {\n")
+	builder.WriteString("\tnode [shape=box, fontname=\"monospace\"];\n")
+	if root := trace.Root(); root != nil {
+		id := 0
+		writeDOTNode(&builder, root, &id)
+	}
+	builder.WriteString("}\n")
+	_, err := io.WriteString(w, builder.String())
+	return err
+}
+
+// Writes node and everything under it. Returns the id of node
+func writeDOTNode(builder *strings.Builder, node *TraceNode, id *int) int {
+	nodeID := *id
+	*id++
+	label := fmt.Sprintf("depth %d\\n[%s, %s]\\n%s", node.Depth, formatBound(node.Alpha), formatBound(node.Beta), FormatScore(node.Score))
+	style := ""
+	if node.Cutoff {
+		style += ", color=red"
+	}
+	if node.Stopped {
+		style += ", style=dashed"
+	}
+	fmt.Fprintf(builder, "\tn%d [label=\"%s\"%s];\n", nodeID, label, style)
+	for _, child := range node.Children {
+		childID := writeDOTNode(builder, child, id)
+		fmt.Fprintf(builder, "\tn%d -> n%d [label=\"%s\"];\n", nodeID, childID, child.Move)
+	}
+	return nodeID
+}
+
+// The full window is written as infinity
+func formatBound(bound float32) string {
+	switch {
+	case bound >= bestMax:
+		return "inf"
+	case bound <= bestMin:
+		return "-inf"
+	}
+	return FormatScore(bound)
+}
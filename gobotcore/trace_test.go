@@ -0,0 +1,119 @@
+package gobotcore
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestTrace_Tree(t *testing.T) {
+	board := NewDefaultBoard()
+	human := Player(HUMAN)
+	trace := NewTrace(0, 0)
+	best := board.SearchWithOptions(&human, SearchLimits{Depth: 3}, SearchOptions{Trace: trace}, nil, nil)
+	plain := board.SearchWithOptions(&human, SearchLimits{Depth: 3}, SearchOptions{Deterministic: true}, nil, nil)
+	if !best.move.Equals(&plain.move) || best.score != plain.score {
+		t.Error("Tracing shouldn't change the result, got", best.move.ToString(), "instead of", plain.move.ToString())
+	}
+
+	root := trace.Root()
+	if root == nil || root.Depth != 3 || root.Score != best.score {
+		t.Fatalf("The root should be the last iteration with the best score, got %+v", root)
+	}
+	moves := board.LegalMovesForPlayer(human)
+	for _, move := range moves {
+		found := false
+		for _, child := range root.Children {
+			found = found || child.Move == move.ToString()
+		}
+		if !found {
+			t.Error("Every root move should be in the tree, missing", move.ToString())
+		}
+	}
+	var check func(node *TraceNode)
+	check = func(node *TraceNode) {
+		for _, child := range node.Children {
+			// The search that verifies a null move is of the node itself
+			if (child.Ply != node.Ply+1 && child.Move != "verify") || child.Move == "" {
+				t.Errorf("Children should be one ply deeper and have a move, got %+v under %+v", child, node)
+			}
+			check(child)
+		}
+	}
+	check(root)
+}
+
+func TestTrace_Limits(t *testing.T) {
+	board := NewDefaultBoard()
+	human := Player(HUMAN)
+	trace := NewTrace(1, 0)
+	board.SearchWithOptions(&human, SearchLimits{Depth: 4}, SearchOptions{Trace: trace}, nil, nil)
+	if ply := deepestTracePly(trace.Root()); ply != 1 {
+		t.Error("Only one ply should be recorded, got", ply)
+	}
+
+	trace = NewTrace(0, 10)
+	board.SearchWithOptions(&human, SearchLimits{Depth: 4}, SearchOptions{Trace: trace}, nil, nil)
+	if count := countTraceNodes(trace.Root()); count != 10 || !trace.Truncated() {
+		t.Error("Should stop recording at 10 nodes, got", count)
+	}
+}
+
+func deepestTracePly(node *TraceNode) int {
+	deepest := node.Ply
+	for _, child := range node.Children {
+		if ply := deepestTracePly(child); ply > deepest {
+			deepest = ply
+		}
+	}
+	return deepest
+}
+
+func countTraceNodes(node *TraceNode) int {
+	count := 1
+	for _, child := range node.Children {
+		count += countTraceNodes(child)
+	}
+	return count
+}
+
+func TestTrace_Write(t *testing.T) {
+	board, player, _ := ParsePosition("6/6/6/6/6/6/2B3/1r1k2 g")
+	trace := NewTrace(0, 0)
+	board.SearchWithOptions(&player, SearchLimits{Depth: 2}, SearchOptions{Trace: trace}, nil, nil)
+
+	var dot bytes.Buffer
+	if err := trace.WriteDOT(&dot); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(dot.String(), "digraph search {") || !strings.Contains(dot.String(), "[label=\"C2D1\"]") {
+		t.Error("Should write a graph with the winning move, got " + dot.String())
+	}
+
+	var out bytes.Buffer
+	if err := trace.WriteJSON(&out); err != nil {
+		t.Fatal(err)
+	}
+	var root TraceNode
+	if err := json.Unmarshal(out.Bytes(), &root); err != nil || countTraceNodes(&root) != countTraceNodes(trace.Root()) {
+		t.Error("Should write the whole tree as JSON, got " + out.String())
+	}
+}
+
+func TestTrace_MultiPV(t *testing.T) {
+	board := NewDefaultBoard()
+	human := Player(HUMAN)
+	trace := NewTrace(1, 0)
+	board.SearchWithOptions(&human, SearchLimits{Depth: 3}, SearchOptions{Trace: trace, MultiPV: 3}, nil, nil)
+	root := trace.Root()
+	for _, move := range board.LegalMovesForPlayer(human) {
+		found := false
+		for _, child := range root.Children {
+			found = found || child.Move == move.ToString()
+		}
+		if !found {
+			t.Error("Every root move should be in the tree with MultiPV, missing", move.ToString())
+		}
+	}
+}
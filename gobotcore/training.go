@@ -0,0 +1,163 @@
+package gobotcore
+
+import (
+	"math"
+	"math/rand"
+)
+
+// A position to learn from, usually from a self-play game
+type TrainingPosition struct {
+	Board  Board
+	Score  float32 // What the search thought of the position, from Gobot's point of view
+	Result float32 // 1 if Gobot went on to win the game, 0 if Human did, 0.5 if the game wasn't finished
+}
+
+type TrainingOptions struct {
+	Epochs       int
+	LearningRate float32
+	// How much the game result counts in what the network learns, against the search score. From 0 to 1
+	ResultWeight float32
+	Seed         int64 // For shuffling the positions
+}
+
+func sigmoid(x float32) float32 {
+	return float32(1 / (1 + math.Exp(-float64(x))))
+}
+
+/* Train teaches the network the positions with plain stochastic gradient descent, in a new random order every epoch.
+ * The network learns the win chance of each position: the chance of the search score mixed with the game result.
+ * Every position is also learned with the board turned around and the players swapped, so the network scores both sides alike.
+ * progress is called after every epoch with the mean squared error of the win chances, and may be nil. Returns the last error
+ */
+func (network *Network) Train(positions []TrainingPosition, options TrainingOptions, progress func(epoch int, loss float32)) float32 {
+	samples := make([]TrainingPosition, 0, len(positions)*2)
+	for _, position := range positions {
+		samples = append(samples, position, TrainingPosition{
+			Board:  swapSides(&position.Board),
+			Score:  -position.Score,
+			Result: 1 - position.Result,
+		})
+	}
+
+	random := rand.New(rand.NewSource(options.Seed))
+	var loss float32
+	for epoch := 1; epoch <= options.Epochs; epoch++ {
+		random.Shuffle(len(samples), func(i, j int) {
+			samples[i], samples[j] = samples[j], samples[i]
+		})
+		var total float32
+		for i := range samples {
+			total += network.learn(&samples[i], options)
+		}
+		loss = total / float32(len(samples))
+		if progress != nil {
+			progress(epoch, loss)
+		}
+	}
+	network.quantize()
+	return loss
+}
+
+// The mean squared error of the network's win chances on the positions, like Train reports it
+func (network *Network) Loss(positions []TrainingPosition, resultWeight float32) float32 {
+	var total float32
+	for i := range positions {
+		chance := sigmoid(network.output(network.activations(&positions[i].Board)))
+		err := chance - trainingTarget(&positions[i], resultWeight)
+		total += err * err
+	}
+	return total / float32(len(positions))
+}
+
+func trainingTarget(position *TrainingPosition, resultWeight float32) float32 {
+	return resultWeight*position.Result + (1-resultWeight)*sigmoid(position.Score/networkScoreScale)
+}
+
+// One step of gradient descent on the position. Returns the squared error before the step
+func (network *Network) learn(position *TrainingPosition, options TrainingOptions) float32 {
+	// Forward, keeping what backpropagation needs
+	sums1 := network.firstLayer(&position.Board)
+	activations1 := make([]float32, network.hidden1)
+	for i, sum := range sums1 {
+		activations1[i] = clippedReLU(sum)
+	}
+	sums2 := make([]float32, network.hidden2)
+	activations2 := make([]float32, network.hidden2)
+	output := network.biases3[0]
+	for j := range sums2 {
+		sums2[j] = network.biases2[j]
+		for i, activation := range activations1 {
+			sums2[j] += activation * network.weights2[i*network.hidden2+j]
+		}
+		activations2[j] = clippedReLU(sums2[j])
+		output += activations2[j] * network.weights3[j]
+	}
+
+	chance := sigmoid(output)
+	err := chance - trainingTarget(position, options.ResultWeight)
+	gradient := 2 * err * chance * (1 - chance)
+	rate := options.LearningRate
+
+	// Backward. The gradient of a clipped ReLU is 1 between the clips and 0 outside them
+	gradients2 := make([]float32, network.hidden2)
+	for j := range gradients2 {
+		if sums2[j] > 0 && sums2[j] < 1 {
+			gradients2[j] = gradient * network.weights3[j]
+		}
+		network.weights3[j] -= rate * gradient * activations2[j]
+	}
+	network.biases3[0] -= rate * gradient
+
+	gradients1 := make([]float32, network.hidden1)
+	for i, activation := range activations1 {
+		var sum float32
+		for j, gradient2 := range gradients2 {
+			sum += gradient2 * network.weights2[i*network.hidden2+j]
+			network.weights2[i*network.hidden2+j] -= rate * gradient2 * activation
+		}
+		if sums1[i] > 0 && sums1[i] < 1 {
+			gradients1[i] = sum
+		}
+	}
+	for j, gradient2 := range gradients2 {
+		network.biases2[j] -= rate * gradient2
+	}
+
+	for i, gradient1 := range gradients1 {
+		network.biases1[i] = clampFirstLayer(network.biases1[i] - rate*gradient1)
+	}
+	var row, col int8
+	for row = 0; row < boardRows; row++ {
+		for col = 0; col < boardCols; col++ {
+			location := Location{row: row, col: col}
+			piece := position.Board.PieceAt(&location)
+			if piece.IsEmpty() {
+				continue
+			}
+			weights := network.weights1[featureIndex(piece, location)*network.hidden1:]
+			for i, gradient1 := range gradients1 {
+				weights[i] = clampFirstLayer(weights[i] - rate*gradient1)
+			}
+		}
+	}
+	return err * err
+}
+
+// The board turned around with the players swapped, so Human stands where Gobot did
+func swapSides(board *Board) Board {
+	swapped := Board{}
+	var row, col int8
+	for row = 0; row < boardRows; row++ {
+		for col = 0; col < boardCols; col++ {
+			piece := board[row][col]
+			switch {
+			case piece >= BISHOP_GOB && piece <= KING_GOB:
+				piece += BISHOP_HUM - BISHOP_GOB
+			case piece >= BISHOP_HUM:
+				piece -= BISHOP_HUM - BISHOP_GOB
+			}
+			swapped[boardRows-1-row][boardCols-1-col] = piece
+		}
+	}
+	return swapped
+}
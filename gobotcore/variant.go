@@ -0,0 +1,88 @@
+package gobotcore
+
+import (
+	"errors"
+	"strings"
+	"sync"
+)
+
+/* A variant is a Morph-like game on a board of another size. The pieces move and morph the same way,
+ * only the size of the board and the start position change. Squares are still named by a column letter
+ * and a row number, so a board can be at most maxBoardCols wide and maxBoardRows high.
+ * The variant is set for the whole program with SetVariant, since every board, move and position depends on it.
+ * It must not be changed while a game is going on, and SetVariant refuses to while a search is running
+ */
+type Variant struct {
+	Name  string
+	Cols  int8
+	Rows  int8
+	Start string // The start position, see ParsePosition
+}
+
+const (
+	maxBoardCols int8 = 8
+	maxBoardRows int8 = 9
+)
+
+var Variants = []Variant{
+	{Name: "standard", Cols: 6, Rows: 8, Start: StartPosition},
+	{Name: "mini", Cols: 5, Rows: 6, Start: "1K3/NBRBN/2P2/2p2/nbrbn/3k1 h"},
+	{Name: "large", Cols: 8, Rows: 8, Start: "2K5/NBRBBRBN/2PPPP2/8/8/2pppp2/nbrbbrbn/5k2 h"},
+}
+
+var (
+	variant    = Variants[0]
+	boardCols  = variant.Cols
+	boardRows  = variant.Rows
+	startBoard = mustParseStart(variant)
+	// Officers in the start position, for telling the game phase. See estimateMovesLeft
+	startOfficers = startBoard.countOfficers()
+
+	// Every search holds it for reading while it runs, see newSearchControl, so the board can't change size under it
+	variantLock sync.RWMutex
+)
+
+func ParseVariant(name string) (Variant, error) {
+	names := make([]string, len(Variants))
+	for i, variant := range Variants {
+		if strings.EqualFold(variant.Name, name) {
+			return variant, nil
+		}
+		names[i] = variant.Name
+	}
+	return Variant{}, errors.New("variant must be one of " + strings.Join(names, ", "))
+}
+
+// SetVariant switches every board to the size of the variant. The variant is left as it was if its start position doesn't fit its board
+func SetVariant(newVariant Variant) error {
+	if !variantLock.TryLock() {
+		return errors.New("the variant can't be changed while a search is running")
+	}
+	defer variantLock.Unlock()
+	if newVariant.Cols < 1 || newVariant.Cols > maxBoardCols || newVariant.Rows < 1 || newVariant.Rows > maxBoardRows {
+		return errors.New("a board can be from 1x1 to 8x9 squares")
+	}
+	oldCols, oldRows := boardCols, boardRows
+	boardCols, boardRows = newVariant.Cols, newVariant.Rows
+	start, _, err := ParsePosition(newVariant.Start)
+	if err != nil {
+		boardCols, boardRows = oldCols, oldRows
+		return errors.New("start position of " + newVariant.Name + ": " + err.Error())
+	}
+	variant = newVariant
+	startBoard = start
+	startOfficers = start.countOfficers()
+	return nil
+}
+
+func CurrentVariant() Variant {
+	return variant
+}
+
+func mustParseStart(variant Variant) Board {
+	board, _, err := ParsePosition(variant.Start)
+	if err != nil {
+		panic(err)
+	}
+	return board
+}
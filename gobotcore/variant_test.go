@@ -0,0 +1,112 @@
+package gobotcore
+
+import "testing"
+
+// Runs test with every variant in turn, and goes back to the standard board afterwards
+func forEachVariant(t *testing.T, test func(variant Variant)) {
+	defer SetVariant(Variants[0])
+	for _, variant := range Variants {
+		if err := SetVariant(variant); err != nil {
+			t.Fatal(err)
+		}
+		test(variant)
+	}
+}
+
+func TestVariant_StartPosition(t *testing.T) {
+	forEachVariant(t, func(variant Variant) {
+		board := NewDefaultBoard()
+		if position := board.Position(HUMAN); position != variant.Start {
+			t.Errorf("%s: the start board should write as %s, got %s", variant.Name, variant.Start, position)
+		}
+		cols, rows := BoardSize()
+		if cols != variant.Cols || rows != variant.Rows {
+			t.Errorf("%s: the board should be %dx%d, got %dx%d", variant.Name, variant.Cols, variant.Rows, cols, rows)
+		}
+		// The start is the same for both players, turned around
+		if swapSides(&board) != board {
+			t.Errorf("%s: the start position should be the same from both sides", variant.Name)
+		}
+	})
+}
+
+func TestVariant_Notation(t *testing.T) {
+	forEachVariant(t, func(variant Variant) {
+		corner := Location{col: variant.Cols - 1, row: variant.Rows - 1}
+		name := corner.ToString()
+		if parsed, err := ParseLocation(name); err != nil || parsed != corner {
+			t.Errorf("%s: %s should parse back to the top right corner", variant.Name, name)
+		}
+		if variant.Cols < maxBoardCols {
+			offBoard := string(alphabet[variant.Cols]) + "1"
+			if _, err := ParseLocation(offBoard); err == nil {
+				t.Errorf("%s: %s should be off the board", variant.Name, offBoard)
+			}
+		}
+		// Flipped, the top right corner is the bottom left one
+		if flipped := ToStringMultipleLocationsFlipped(corner, corner); flipped != "A1A1" {
+			t.Errorf("%s: the corner flipped should be A1, got %s", variant.Name, flipped)
+		}
+	})
+}
+
+func TestVariant_RenderAndParse(t *testing.T) {
+	forEachVariant(t, func(variant Variant) {
+		board := NewDefaultBoard()
+		drawn := Renderer{}.Render(&board)
+		if parsed := NewBoardFromString(drawn); parsed != board {
+			t.Errorf("%s: the drawn board should read back the same:\n%s", variant.Name, drawn)
+		}
+	})
+}
+
+func TestVariant_Search(t *testing.T) {
+	SetDebug(false)
+	forEachVariant(t, func(variant Variant) {
+		board := NewDefaultBoard()
+		player := Player(HUMAN)
+		best := board.SearchWithOptions(&player, SearchLimits{Depth: 4}, deterministic, nil, nil)
+		if !board.IsValidMoveForPlayer(best.Move(), player) {
+			t.Errorf("%s: the search should find a legal move, got %s", variant.Name, best.Move().ToString())
+		}
+		// Nothing may ever be put past the edge of the board
+		for _, move := range board.LegalMovesForPlayer(player) {
+			if !move.from.IsOnBoard() || !move.to.IsOnBoard() {
+				t.Errorf("%s: %s goes off the board", variant.Name, move.ToString())
+			}
+		}
+	})
+}
+
+func TestSetVariant_Invalid(t *testing.T) {
+	defer SetVariant(Variants[0])
+	if err := SetVariant(Variant{Name: "broken", Cols: 5, Rows: 6, Start: StartPosition}); err == nil {
+		t.Error("A start position of the wrong size should be an error")
+	}
+	if err := SetVariant(Variant{Name: "huge", Cols: 10, Rows: 10, Start: "10/10/10/10/10/10/10/10/10/10 h"}); err == nil {
+		t.Error("A board wider than the letters should be an error")
+	}
+	if CurrentVariant().Name != "standard" || NewDefaultBoard() != mustParseStart(Variants[0]) {
+		t.Error("A variant that can't be set should leave the old one")
+	}
+	if _, err := ParseVariant("MINI"); err != nil {
+		t.Error("Variant names shouldn't care about case")
+	}
+}
+
+func TestSetVariant_DuringSearch(t *testing.T) {
+	board := NewDefaultBoard()
+	player := Player(HUMAN)
+	stop := make(chan struct{})
+	ponder := board.StartPonder(&player, SearchLimits{}, SearchOptions{}, stop, nil)
+	if err := SetVariant(Variants[1]); err == nil {
+		SetVariant(Variants[0])
+		t.Error("The variant shouldn't change while a search is running")
+	}
+	close(stop)
+	ponder.Wait()
+	if err := SetVariant(Variants[1]); err != nil {
+		t.Error("The variant should change once the search is over, got", err)
+	}
+	SetVariant(Variants[0])
+}
@@ -0,0 +1,65 @@
+package gobotimage
+
+import (
+	"image"
+	"image/color"
+	"image/color/palette"
+	"image/draw"
+	"image/gif"
+	"io"
+
+	"github.com/ktodaz/gobot/gobotcore"
+)
+
+// Writes an animated GIF of a whole game, one frame per position with the last move highlighted.
+// delay is the time each frame is shown in hundredths of a second. The final position is shown three times as long
+func WriteGIF(w io.Writer, game *gobotcore.Game, options Options, delay int) error {
+	start, toMove := game.Start()
+	replay := gobotcore.NewGameFromBoard(start, toMove)
+
+	frames := []*image.RGBA{RenderImage(&start, options)}
+	for _, played := range game.History() {
+		if err := replay.MakeMove(played.Move); err != nil {
+			return err
+		}
+		board := replay.Board()
+		frameOptions := options
+		frameOptions.Highlights = append([]gobotcore.Location{*played.Move.From(), *played.Move.To()}, options.Highlights...)
+		frames = append(frames, RenderImage(&board, frameOptions))
+	}
+
+	framePalette := exactPalette(frames)
+	animation := &gif.GIF{}
+	for i, frame := range frames {
+		paletted := image.NewPaletted(frame.Bounds(), framePalette)
+		draw.Draw(paletted, paletted.Bounds(), frame, image.Point{}, draw.Src)
+		animation.Image = append(animation.Image, paletted)
+		if i == len(frames)-1 {
+			animation.Delay = append(animation.Delay, delay*3)
+		} else {
+			animation.Delay = append(animation.Delay, delay)
+		}
+	}
+	return gif.EncodeAll(w, animation)
+}
+
+// Boards are drawn with few flat colors, so they usually fit in one GIF palette exactly.
+// Falls back to the Plan 9 palette if they don't
+func exactPalette(frames []*image.RGBA) color.Palette {
+	seen := map[color.RGBA]bool{}
+	var colors color.Palette
+	for _, frame := range frames {
+		for i := 0; i+3 < len(frame.Pix); i += 4 {
+			c := color.RGBA{frame.Pix[i], frame.Pix[i+1], frame.Pix[i+2], frame.Pix[i+3]}
+			if seen[c] {
+				continue
+			}
+			if len(colors) == 256 {
+				return palette.Plan9
+			}
+			seen[c] = true
+			colors = append(colors, c)
+		}
+	}
+	return colors
+}
@@ -0,0 +1,278 @@
+/* Package gobotimage draws boards as SVG, PNG and animated GIF using only the standard library.
+ * Pieces are drawn as discs with the piece's letter on them: dark discs for Gobot and light discs for Human.
+ */
+package gobotimage
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"io"
+	"math"
+	"strings"
+
+	"github.com/ktodaz/gobot/gobotcore"
+)
+
+const defaultSquareSize = 60
+
+var (
+	backgroundColor  = color.NRGBA{0xf4, 0xf1, 0xea, 0xff}
+	lightSquareColor = color.NRGBA{0xee, 0xd8, 0xb5, 0xff}
+	darkSquareColor  = color.NRGBA{0xb5, 0x88, 0x63, 0xff}
+	highlightColor   = color.NRGBA{0xff, 0xe6, 0x00, 0x73}
+	coordinateColor  = color.NRGBA{0x55, 0x55, 0x55, 0xff}
+	gobotPieceColor  = color.NRGBA{0x2b, 0x2b, 0x2b, 0xff}
+	humanPieceColor  = color.NRGBA{0xfa, 0xfa, 0xfa, 0xff}
+	arrowColor       = color.NRGBA{0x1e, 0x6e, 0xc8, 0xff}
+)
+
+type Options struct {
+	SquareSize  int  // Width of a square in pixels. Defaults to 60
+	Coordinates bool // Draw row numbers and column letters around the board
+	Flipped     bool // Draw the board from Gobot's side, like gobotcore.Renderer
+	Highlights  []gobotcore.Location
+	// Draws an arrow for every move, e.g. the last move or a principal variation. Later arrows are fainter
+	Arrows gobotcore.Moves
+}
+
+// Where everything goes in the picture
+type layout struct {
+	size       int
+	margin     int
+	cols, rows int
+	flipped    bool
+}
+
+type point struct {
+	x, y float64
+}
+
+func newLayout(options Options) layout {
+	cols, rows := gobotcore.BoardSize()
+	l := layout{size: options.SquareSize, cols: int(cols), rows: int(rows), flipped: options.Flipped}
+	if l.size <= 0 {
+		l.size = defaultSquareSize
+	}
+	if options.Coordinates {
+		l.margin = l.size / 2
+	}
+	return l
+}
+
+func (l layout) width() int {
+	return l.cols*l.size + 2*l.margin
+}
+
+func (l layout) height() int {
+	return l.rows*l.size + 2*l.margin
+}
+
+// Top left corner of a square in pixels
+func (l layout) squareOrigin(col int, row int) (int, int) {
+	x, y := col, l.rows-1-row
+	if l.flipped {
+		x, y = l.cols-1-col, row
+	}
+	return l.margin + x*l.size, l.margin + y*l.size
+}
+
+func (l layout) squareCenter(location gobotcore.Location) point {
+	x, y := l.squareOrigin(int(location.Col()), int(location.Row()))
+	return point{float64(x) + float64(l.size)/2, float64(y) + float64(l.size)/2}
+}
+
+// Labels for the coordinates, in the order they are drawn. A flipped board is relabeled from Gobot's side
+func (l layout) columnLabel(i int) string {
+	return string(rune('A' + i))
+}
+
+func (l layout) rowLabel(i int) string {
+	return string(rune('0' + l.rows - i))
+}
+
+// Outline of an arrow from the center of one square to another, as a polygon
+func (l layout) arrowPolygon(move gobotcore.Move) []point {
+	from, to := l.squareCenter(*move.From()), l.squareCenter(*move.To())
+	dx, dy := to.x-from.x, to.y-from.y
+	length := math.Hypot(dx, dy)
+	if length == 0 {
+		return nil
+	}
+	ux, uy := dx/length, dy/length // Along the arrow
+	nx, ny := -uy, ux              // Across the arrow
+
+	shaft := float64(l.size) / 12
+	head := float64(l.size) / 4
+	headLength := math.Min(float64(l.size)/3, length/2)
+	neck := point{to.x - ux*headLength, to.y - uy*headLength}
+	return []point{
+		{from.x + nx*shaft, from.y + ny*shaft},
+		{neck.x + nx*shaft, neck.y + ny*shaft},
+		{neck.x + nx*head, neck.y + ny*head},
+		to,
+		{neck.x - nx*head, neck.y - ny*head},
+		{neck.x - nx*shaft, neck.y - ny*shaft},
+		{from.x - nx*shaft, from.y - ny*shaft},
+	}
+}
+
+// Later arrows in a line are drawn fainter
+func arrowOpacity(i int) float64 {
+	return math.Max(0.85-0.2*float64(i), 0.3)
+}
+
+func pieceColors(piece gobotcore.Piece) (color.NRGBA, color.NRGBA) {
+	gobot := gobotcore.Player(gobotcore.GOBOT)
+	if piece.IsOwnedBy(&gobot) {
+		return gobotPieceColor, humanPieceColor
+	}
+	return humanPieceColor, gobotPieceColor
+}
+
+// The letter drawn on a piece, the same for both sides. Knights are N, like GetName
+func pieceLetter(piece gobotcore.Piece) string {
+	return strings.ToUpper(piece.GetName())
+}
+
+// ================== Raster ==================
+
+func RenderImage(board *gobotcore.Board, options Options) *image.RGBA {
+	l := newLayout(options)
+	img := image.NewRGBA(image.Rect(0, 0, l.width(), l.height()))
+	draw.Draw(img, img.Bounds(), &image.Uniform{backgroundColor}, image.Point{}, draw.Src)
+
+	for row := 0; row < l.rows; row++ {
+		for col := 0; col < l.cols; col++ {
+			x, y := l.squareOrigin(col, row)
+			squareColor := lightSquareColor
+			if (col+row)%2 == 0 {
+				squareColor = darkSquareColor
+			}
+			fillRect(img, image.Rect(x, y, x+l.size, y+l.size), squareColor)
+		}
+	}
+	for _, location := range options.Highlights {
+		x, y := l.squareOrigin(int(location.Col()), int(location.Row()))
+		fillRect(img, image.Rect(x, y, x+l.size, y+l.size), highlightColor)
+	}
+
+	if options.Coordinates {
+		scale := maxInt(l.size/30, 1)
+		for i := 0; i < l.cols; i++ {
+			x := l.margin + i*l.size + l.size/2 - glyphWidth*scale/2
+			drawText(img, l.columnLabel(i), x, l.height()-l.margin/2-glyphHeight*scale/2, scale, coordinateColor)
+		}
+		for i := 0; i < l.rows; i++ {
+			y := l.margin + i*l.size + l.size/2 - glyphHeight*scale/2
+			drawText(img, l.rowLabel(i), l.margin/2-glyphWidth*scale/2, y, scale, coordinateColor)
+		}
+	}
+
+	for row := 0; row < l.rows; row++ {
+		for col := 0; col < l.cols; col++ {
+			location := gobotcore.NewLocation(int8(col), int8(row))
+			piece := board.PieceAt(&location)
+			if piece.IsEmpty() {
+				continue
+			}
+			fill, ink := pieceColors(piece)
+			center := l.squareCenter(location)
+			radius := float64(l.size) * 0.38
+			fillCircle(img, center, radius, ink)
+			fillCircle(img, center, radius-math.Max(1, float64(l.size)/30), fill)
+			scale := maxInt(l.size/15, 1)
+			drawText(img, pieceLetter(piece), int(center.x)-glyphWidth*scale/2, int(center.y)-glyphHeight*scale/2, scale, ink)
+		}
+	}
+
+	for i, move := range options.Arrows {
+		arrow := arrowColor
+		arrow.A = uint8(arrowOpacity(i) * 255)
+		fillPolygon(img, l.arrowPolygon(move), arrow)
+	}
+	return img
+}
+
+func WritePNG(w io.Writer, board *gobotcore.Board, options Options) error {
+	return png.Encode(w, RenderImage(board, options))
+}
+
+func fillRect(img *image.RGBA, rect image.Rectangle, c color.NRGBA) {
+	draw.Draw(img, rect, &image.Uniform{c}, image.Point{}, draw.Over)
+}
+
+func fillCircle(img *image.RGBA, center point, radius float64, c color.NRGBA) {
+	mask := image.NewAlpha(img.Bounds())
+	for y := int(center.y - radius); y <= int(center.y+radius); y++ {
+		for x := int(center.x - radius); x <= int(center.x+radius); x++ {
+			if math.Hypot(float64(x)+0.5-center.x, float64(y)+0.5-center.y) <= radius {
+				mask.SetAlpha(x, y, color.Alpha{0xff})
+			}
+		}
+	}
+	draw.DrawMask(img, img.Bounds(), &image.Uniform{c}, image.Point{}, mask, image.Point{}, draw.Over)
+}
+
+// Fills the polygon through a mask, so overlapping parts are not blended twice
+func fillPolygon(img *image.RGBA, polygon []point, c color.NRGBA) {
+	if len(polygon) == 0 {
+		return
+	}
+	minX, minY, maxX, maxY := polygon[0].x, polygon[0].y, polygon[0].x, polygon[0].y
+	for _, p := range polygon {
+		minX, minY = math.Min(minX, p.x), math.Min(minY, p.y)
+		maxX, maxY = math.Max(maxX, p.x), math.Max(maxY, p.y)
+	}
+
+	mask := image.NewAlpha(img.Bounds())
+	for y := int(minY); y <= int(maxY); y++ {
+		for x := int(minX); x <= int(maxX); x++ {
+			if insidePolygon(point{float64(x) + 0.5, float64(y) + 0.5}, polygon) {
+				mask.SetAlpha(x, y, color.Alpha{0xff})
+			}
+		}
+	}
+	draw.DrawMask(img, img.Bounds(), &image.Uniform{c}, image.Point{}, mask, image.Point{}, draw.Over)
+}
+
+// Even-odd rule
+func insidePolygon(p point, polygon []point) bool {
+	inside := false
+	for i, j := 0, len(polygon)-1; i < len(polygon); j, i = i, i+1 {
+		a, b := polygon[i], polygon[j]
+		if (a.y > p.y) != (b.y > p.y) && p.x < (b.x-a.x)*(p.y-a.y)/(b.y-a.y)+a.x {
+			inside = !inside
+		}
+	}
+	return inside
+}
+
+// Draws text with the built in font, scaled up scale times, with its top left corner at x, y
+func drawText(img *image.RGBA, text string, x int, y int, scale int, c color.NRGBA) {
+	for _, char := range text {
+		glyph, ok := glyphs[char]
+		if !ok {
+			x += (glyphWidth + 1) * scale
+			continue
+		}
+		for gy, line := range glyph {
+			for gx, pixel := range line {
+				if pixel != '#' {
+					continue
+				}
+				px, py := x+gx*scale, y+gy*scale
+				fillRect(img, image.Rect(px, py, px+scale, py+scale), c)
+			}
+		}
+		x += (glyphWidth + 1) * scale
+	}
+}
+
+func maxInt(a int, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,117 @@
+package gobotimage
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/png"
+	"strings"
+	"testing"
+
+	"github.com/ktodaz/gobot/gobotcore"
+)
+
+func sameColor(a color.Color, b color.Color) bool {
+	r1, g1, b1, a1 := a.RGBA()
+	r2, g2, b2, a2 := b.RGBA()
+	return r1 == r2 && g1 == g2 && b1 == b2 && a1 == a2
+}
+
+func TestWritePNG(t *testing.T) {
+	board := gobotcore.NewDefaultBoard()
+	var buffer bytes.Buffer
+	if err := WritePNG(&buffer, &board, Options{}); err != nil {
+		t.Fatal(err)
+	}
+	img, err := png.Decode(&buffer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if size := img.Bounds().Size(); size != image.Pt(360, 480) {
+		t.Error("A 6x8 board of 60 pixel squares should be 360x480, is", size)
+	}
+	// A1 is a dark square in the bottom left corner
+	if !sameColor(img.At(5, 425), darkSquareColor) {
+		t.Error("A1 should be dark, is", img.At(5, 425))
+	}
+	// Gobot's king on B8 is a dark disc, centered at 90, 30
+	if !sameColor(img.At(72, 30), gobotPieceColor) {
+		t.Error("Gobot's king should be a dark disc, is", img.At(72, 30))
+	}
+	// Human's king on E1 is a light disc, centered at 270, 450
+	if !sameColor(img.At(252, 450), humanPieceColor) {
+		t.Error("Human's king should be a light disc, is", img.At(252, 450))
+	}
+}
+
+func TestRenderImage_Options(t *testing.T) {
+	board := gobotcore.NewDefaultBoard()
+	a1, _ := gobotcore.ParseLocation("A1")
+	move, _ := gobotcore.ParseMove("C3C5")
+	img := RenderImage(&board, Options{SquareSize: 20, Coordinates: true, Highlights: []gobotcore.Location{a1}, Arrows: gobotcore.Moves{move}})
+
+	if size := img.Bounds().Size(); size != image.Pt(6*20+20, 8*20+20) {
+		t.Error("Coordinates should add half a square on every side, size is", size)
+	}
+	// A1 starts at 10, 150 after the margin
+	if sameColor(img.At(12, 152), darkSquareColor) {
+		t.Error("A1 should be highlighted")
+	}
+	// The arrow passes over C4, which is empty
+	if sameColor(img.At(60, 100), lightSquareColor) || sameColor(img.At(60, 100), darkSquareColor) {
+		t.Error("The arrow should cover C4, found", img.At(60, 100))
+	}
+
+	flipped := RenderImage(&board, Options{SquareSize: 20, Flipped: true})
+	// Flipped, Gobot's king is in the bottom row at the fifth column
+	if !sameColor(flipped.At(84, 150), gobotPieceColor) {
+		t.Error("Flipped board should have Gobot's king at the bottom, found", flipped.At(84, 150))
+	}
+}
+
+func TestWriteSVG(t *testing.T) {
+	board := gobotcore.NewDefaultBoard()
+	move, _ := gobotcore.ParseMove("C3C4")
+	var buffer bytes.Buffer
+	if err := WriteSVG(&buffer, &board, Options{Coordinates: true, Arrows: gobotcore.Moves{move}}); err != nil {
+		t.Fatal(err)
+	}
+	svg := buffer.String()
+	if !strings.HasPrefix(svg, `<svg xmlns="http://www.w3.org/2000/svg" width="420" height="540"`) {
+		t.Error("Unexpected svg header: " + svg[:80])
+	}
+	if pieces := strings.Count(svg, `class="piece"`); pieces != 18 {
+		t.Error("Start position has 18 pieces, svg has", pieces)
+	}
+	if arrows := strings.Count(svg, `class="arrow"`); arrows != 1 {
+		t.Error("Expected one arrow, found", arrows)
+	}
+	if !strings.HasSuffix(svg, "</svg>\n") {
+		t.Error("svg should be closed")
+	}
+}
+
+func TestWriteGIF(t *testing.T) {
+	game := gobotcore.NewGame(gobotcore.HUMAN)
+	for _, str := range []string{"C3C4", "D6D5", "C4D5"} {
+		move, _ := gobotcore.ParseMove(str)
+		if err := game.MakeMove(move); err != nil {
+			t.Fatal(err)
+		}
+	}
+	var buffer bytes.Buffer
+	if err := WriteGIF(&buffer, game, Options{SquareSize: 10}, 50); err != nil {
+		t.Fatal(err)
+	}
+	animation, err := gif.DecodeAll(&buffer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(animation.Image) != 4 {
+		t.Error("Expected a frame for the start and every move, got", len(animation.Image))
+	}
+	if animation.Delay[0] != 50 || animation.Delay[3] != 150 {
+		t.Error("Unexpected delays", animation.Delay)
+	}
+}
@@ -0,0 +1,87 @@
+package gobotimage
+
+import (
+	"bufio"
+	"fmt"
+	"image/color"
+	"io"
+	"strings"
+
+	"github.com/ktodaz/gobot/gobotcore"
+)
+
+// Writes the board as an SVG drawing with the same layout and colors as RenderImage
+func WriteSVG(w io.Writer, board *gobotcore.Board, options Options) error {
+	l := newLayout(options)
+	writer := bufio.NewWriter(w)
+	fmt.Fprintf(writer, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d">`+"\n",
+		l.width(), l.height(), l.width(), l.height())
+	fmt.Fprintf(writer, `<rect width="%d" height="%d" fill="%s"/>`+"\n", l.width(), l.height(), hex(backgroundColor))
+
+	for row := 0; row < l.rows; row++ {
+		for col := 0; col < l.cols; col++ {
+			x, y := l.squareOrigin(col, row)
+			squareColor := lightSquareColor
+			if (col+row)%2 == 0 {
+				squareColor = darkSquareColor
+			}
+			fmt.Fprintf(writer, `<rect x="%d" y="%d" width="%d" height="%d" fill="%s"/>`+"\n", x, y, l.size, l.size, hex(squareColor))
+		}
+	}
+	for _, location := range options.Highlights {
+		x, y := l.squareOrigin(int(location.Col()), int(location.Row()))
+		fmt.Fprintf(writer, `<rect class="highlight" x="%d" y="%d" width="%d" height="%d" fill="%s" fill-opacity="%.2f"/>`+"\n",
+			x, y, l.size, l.size, hex(highlightColor), float64(highlightColor.A)/255)
+	}
+
+	if options.Coordinates {
+		fontSize := l.size / 4
+		for i := 0; i < l.cols; i++ {
+			writeText(writer, l.columnLabel(i), float64(l.margin+i*l.size+l.size/2), float64(l.height()-l.margin/2), fontSize, coordinateColor)
+		}
+		for i := 0; i < l.rows; i++ {
+			writeText(writer, l.rowLabel(i), float64(l.margin/2), float64(l.margin+i*l.size+l.size/2), fontSize, coordinateColor)
+		}
+	}
+
+	for row := 0; row < l.rows; row++ {
+		for col := 0; col < l.cols; col++ {
+			location := gobotcore.NewLocation(int8(col), int8(row))
+			piece := board.PieceAt(&location)
+			if piece.IsEmpty() {
+				continue
+			}
+			fill, ink := pieceColors(piece)
+			center := l.squareCenter(location)
+			fmt.Fprintf(writer, `<circle class="piece" cx="%.1f" cy="%.1f" r="%.1f" fill="%s" stroke="%s" stroke-width="%d"/>`+"\n",
+				center.x, center.y, float64(l.size)*0.37, hex(fill), hex(ink), maxInt(l.size/30, 1))
+			writeText(writer, pieceLetter(piece), center.x, center.y, l.size/2, ink)
+		}
+	}
+
+	for i, move := range options.Arrows {
+		polygon := l.arrowPolygon(move)
+		if polygon == nil {
+			continue
+		}
+		points := make([]string, len(polygon))
+		for j, p := range polygon {
+			points[j] = fmt.Sprintf("%.1f,%.1f", p.x, p.y)
+		}
+		fmt.Fprintf(writer, `<polygon class="arrow" points="%s" fill="%s" fill-opacity="%.2f"/>`+"\n",
+			strings.Join(points, " "), hex(arrowColor), arrowOpacity(i))
+	}
+
+	writer.WriteString("</svg>\n")
+	return writer.Flush()
+}
+
+// Text centered on x, y
+func writeText(writer *bufio.Writer, text string, x float64, y float64, fontSize int, c color.NRGBA) {
+	fmt.Fprintf(writer, `<text x="%.1f" y="%.1f" font-family="sans-serif" font-weight="bold" font-size="%d" fill="%s" text-anchor="middle" dominant-baseline="central">%s</text>`+"\n",
+		x, y, fontSize, hex(c), text)
+}
+
+func hex(c color.NRGBA) string {
+	return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+}
@@ -0,0 +1,138 @@
+package gobotproto
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ktodaz/gobot/gobotcore"
+)
+
+// Client drives an engine that speaks the text protocol, so it can play a game
+type Client struct {
+	Name string // From the engine's "id name" line
+
+	in  io.WriteCloser
+	out *bufio.Scanner
+	cmd *exec.Cmd // nil when the client wasn't started with StartClient
+}
+
+// Starts the engine process and does the handshake
+func StartClient(command string, args ...string) (*Client, error) {
+	cmd := exec.Command(command, args...)
+	in, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	out, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+
+	client, err := NewClient(in, out)
+	if err != nil {
+		cmd.Process.Kill()
+		cmd.Wait()
+		return nil, err
+	}
+	client.cmd = cmd
+	return client, nil
+}
+
+// Talks to an engine that reads commands from in and writes replies to out, and does the handshake
+func NewClient(in io.WriteCloser, out io.Reader) (*Client, error) {
+	client := &Client{in: in, out: bufio.NewScanner(out)}
+	if err := client.send("morph"); err != nil {
+		return nil, err
+	}
+	err := client.readUntil("morphok", func(line string) {
+		if strings.HasPrefix(line, "id name ") {
+			client.Name = strings.TrimPrefix(line, "id name ")
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	return client, nil
+}
+
+func (client *Client) SetOption(name string, value string) error {
+	return client.send("setoption name " + name + " value " + value)
+}
+
+// Asks the engine for its move in the game's current position, thinking for moveTime
+func (client *Client) BestMove(game *gobotcore.Game, moveTime time.Duration) (gobotcore.Move, error) {
+	start, toMove := game.Start()
+	position := "position fen " + start.Position(toMove)
+	if history := game.History(); len(history) > 0 {
+		position += " moves"
+		for _, played := range history {
+			position += " " + played.Move.ToString()
+		}
+	}
+	if err := client.send(position); err != nil {
+		return gobotcore.Move{}, err
+	}
+	if err := client.send("go movetime " + strconv.Itoa(int(moveTime/time.Millisecond))); err != nil {
+		return gobotcore.Move{}, err
+	}
+
+	var fields []string
+	err := client.readUntil("bestmove", func(line string) {
+		if strings.HasPrefix(line, "bestmove") {
+			fields = strings.Fields(line)
+		}
+	})
+	if err != nil {
+		return gobotcore.Move{}, err
+	}
+	// A misbehaving engine may leave the move out
+	if len(fields) < 2 {
+		return gobotcore.Move{}, errors.New(client.Name + " sent bestmove without a move")
+	}
+	bestMove := fields[1]
+	if bestMove == "none" {
+		return gobotcore.Move{}, errors.New(client.Name + " has no move")
+	}
+	return gobotcore.ParseMove(bestMove)
+}
+
+// Tells the engine to quit and waits for its process to end
+func (client *Client) Close() error {
+	client.send("quit")
+	err := client.in.Close()
+	if client.cmd != nil {
+		if waitErr := client.cmd.Wait(); err == nil {
+			err = waitErr
+		}
+	}
+	return err
+}
+
+func (client *Client) send(line string) error {
+	_, err := fmt.Fprintln(client.in, line)
+	return err
+}
+
+// Reads lines until one starts with prefix, passing every line to handle
+func (client *Client) readUntil(prefix string, handle func(line string)) error {
+	for client.out.Scan() {
+		line := client.out.Text()
+		handle(line)
+		if strings.HasPrefix(line, prefix) {
+			return nil
+		}
+	}
+	if err := client.out.Err(); err != nil {
+		return err
+	}
+	return errors.New("engine closed its output while waiting for " + prefix)
+}
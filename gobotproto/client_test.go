@@ -0,0 +1,84 @@
+package gobotproto
+
+import (
+	"bufio"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/ktodaz/gobot/gobotcore"
+)
+
+// Connects a client to an engine running in this process
+func newTestClient(t *testing.T) *Client {
+	commandsIn, commandsOut := io.Pipe()
+	repliesIn, repliesOut := io.Pipe()
+	engine := NewEngine(repliesOut)
+	go func() {
+		engine.Run(commandsIn)
+		repliesOut.Close()
+	}()
+
+	client, err := NewClient(commandsOut, repliesIn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client
+}
+
+func TestClient_BestMove(t *testing.T) {
+	client := newTestClient(t)
+	defer client.Close()
+	if client.Name != "Gobot" {
+		t.Error("Should read the engine's name, got " + client.Name)
+	}
+
+	game := gobotcore.NewGame(gobotcore.HUMAN)
+	game.MakeMove(gobotcore.NewMoveFromString("C3C4"))
+	move, err := client.BestMove(game, 100*time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := game.MakeMove(move); err != nil {
+		t.Error("Engine should play a legal Gobot move, got " + move.ToString())
+	}
+}
+
+func TestClient_NoMove(t *testing.T) {
+	client := newTestClient(t)
+	defer client.Close()
+	// Human has no pieces left, so there is nothing to play
+	game, _ := gobotcore.NewGameFromPosition("K5/6/6/6/6/6/6/6 h")
+	if _, err := client.BestMove(game, 100*time.Millisecond); err == nil {
+		t.Error("Should fail when the engine has no move")
+	}
+}
+
+func TestClient_BestMoveWithoutMove(t *testing.T) {
+	// An engine that answers go with a bare bestmove
+	commandsIn, commandsOut := io.Pipe()
+	repliesIn, repliesOut := io.Pipe()
+	go func() {
+		commands := bufio.NewScanner(commandsIn)
+		for commands.Scan() {
+			switch commands.Text() {
+			case "morph":
+				io.WriteString(repliesOut, "id name Broken\nmorphok\n")
+			case "go movetime 100":
+				io.WriteString(repliesOut, "bestmove \n")
+			case "quit":
+				repliesOut.Close()
+				return
+			}
+		}
+	}()
+	client, err := NewClient(commandsOut, repliesIn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+	_, err = client.BestMove(gobotcore.NewGame(gobotcore.HUMAN), 100*time.Millisecond)
+	if err == nil || err.Error() != "Broken sent bestmove without a move" {
+		t.Error("Should name the engine that sent no move, got", err)
+	}
+}
@@ -0,0 +1,418 @@
+/* Package gobotproto implements a line based text protocol for driving Gobot, modeled after UCI.
+ *
+ * GUI to engine:
+ *	morph                                         Handshake. Engine replies with id, options and "morphok"
+ *	isready                                       Engine replies "readyok"
+ *	newgame                                       Forget the current game
+ *	position startpos [moves C3C4 ...]            Start position with Human (lowercase) to move
+ *	position fen <rows> <g|h> [moves ...]         Any position, see gobotcore.ParsePosition
+ *	go [depth N] [nodes N] [movetime ms] [wtime ms] [btime ms] [winc ms] [binc ms] [movestogo N] [infinite] [ponder]
+ *	   [searchmoves C3C4 ...]                     searchmoves only searches the listed moves and has to come last
+ *	                                              With ponder the position is the one after the expected reply, which is
+ *	                                              the second move of the last pv. Time limits only count from ponderhit
+ *	                                              With infinite or ponder, bestmove waits for stop (or ponderhit) even if the search is done
+ *	ponderhit                                     The opponent played the expected reply, so keep searching
+ *	stop                                          Stop searching and report the best move
+ *	setoption name <name> value <value>           MoveTime, MultiPV, or NullMove, LateMoveReductions, Futility, Quiescence and Deterministic (true or false)
+ *	                                              Deterministic searches give the same output every time, and count time in nodes
+ *	                                              Evaluator is classic or the path of a network weights file from gobot train
+ *	                                              Variant is standard, mini or large. It changes the board size of the whole
+ *	                                              program and starts a new game
+ *	quit
+ *
+ * Engine to GUI:
+ *	info depth N score cp N nodes N time ms pv C3C4 ...
+ *	                                              "score mate N" instead when the side to move captures the king in N moves, negative if it loses
+ *	info depth N multipv K score cp N ...         With MultiPV above 1, one line for each of the best moves, best first
+ *	bestmove C3C4                                 "bestmove none" if there is no legal move
+ *
+ * Moves are always written from the Human side's point of view (gobotcore.Move.ToString), so the GUI
+ * doesn't have to flip anything. wtime/winc are the Human side's clock, btime/binc are Gobot's.
+ */
+package gobotproto
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ktodaz/gobot/gobotcore"
+)
+
+const (
+	engineName   = "Gobot"
+	engineAuthor = "Kyle Szombathy"
+
+	defaultMoveTime = 5 * time.Second
+	maxMultiPV      = 32
+)
+
+type Engine struct {
+	out   io.Writer
+	outMu sync.Mutex
+
+	game     *gobotcore.Game
+	moveTime time.Duration
+	options  gobotcore.SearchOptions
+
+	stop      chan struct{}
+	ponder    *gobotcore.Ponder // The running search if it was started with go ponder
+	ponderHit chan struct{}     // Closed on ponderhit, so the ponder may report its move
+	searching sync.WaitGroup
+}
+
+func NewEngine(out io.Writer) *Engine {
+	return &Engine{
+		out:      out,
+		game:     gobotcore.NewGame(gobotcore.HUMAN),
+		moveTime: defaultMoveTime,
+	}
+}
+
+// Run reads commands from in until "quit" or the end of the input
+func (engine *Engine) Run(in io.Reader) error {
+	scanner := bufio.NewScanner(in)
+	for scanner.Scan() {
+		if !engine.Handle(scanner.Text()) {
+			return nil
+		}
+	}
+	engine.stopSearch()
+	return scanner.Err()
+}
+
+// Handle executes a single command. Returns false when the engine should quit
+func (engine *Engine) Handle(line string) bool {
+	fields := strings.Fields(line)
+	if len(fields) == 0 {
+		return true
+	}
+
+	switch fields[0] {
+	case "morph":
+		engine.writeLine("id name " + engineName)
+		engine.writeLine("id author " + engineAuthor)
+		engine.writeLine(fmt.Sprintf("option name MoveTime type spin default %d min 1 max 3600000", defaultMoveTime/time.Millisecond))
+		engine.writeLine(fmt.Sprintf("option name MultiPV type spin default 1 min 1 max %d", maxMultiPV))
+		engine.writeLine("option name NullMove type check default true")
+		engine.writeLine("option name LateMoveReductions type check default true")
+		engine.writeLine("option name Futility type check default true")
+		engine.writeLine("option name Quiescence type check default true")
+		engine.writeLine("option name Ponder type check default false")
+		engine.writeLine("option name Deterministic type check default false")
+		engine.writeLine("option name Evaluator type string default classic")
+		engine.writeLine("option name Variant type combo default " + gobotcore.CurrentVariant().Name + variantChoices())
+		engine.writeLine("morphok")
+	case "isready":
+		engine.writeLine("readyok")
+	case "newgame":
+		engine.stopSearch()
+		engine.game = gobotcore.NewGame(gobotcore.HUMAN)
+	case "position":
+		engine.stopSearch()
+		engine.position(fields[1:])
+	case "go":
+		engine.stopSearch()
+		engine.goSearch(fields[1:])
+	case "ponderhit":
+		if engine.ponder != nil {
+			engine.ponder.Hit()
+			close(engine.ponderHit)
+			engine.ponder = nil
+			engine.ponderHit = nil
+		}
+	case "stop":
+		engine.stopSearch()
+	case "setoption":
+		engine.setOption(fields[1:])
+	case "quit":
+		engine.stopSearch()
+		return false
+	default:
+		engine.writeLine("info string unknown command " + fields[0])
+	}
+	return true
+}
+
+// Wait blocks until the current search, if any, has reported its best move
+func (engine *Engine) Wait() {
+	engine.searching.Wait()
+}
+
+func (engine *Engine) position(args []string) {
+	if len(args) == 0 {
+		engine.writeLine("info string position needs startpos or fen")
+		return
+	}
+
+	var game *gobotcore.Game
+	var err error
+	movesIndex := indexOf(args, "moves")
+	switch args[0] {
+	case "startpos":
+		game = gobotcore.NewGame(gobotcore.HUMAN)
+	case "fen":
+		end := movesIndex
+		if end == -1 {
+			end = len(args)
+		}
+		game, err = gobotcore.NewGameFromPosition(strings.Join(args[1:end], " "))
+	default:
+		engine.writeLine("info string position needs startpos or fen")
+		return
+	}
+	if err != nil {
+		engine.writeLine("info string bad position: " + err.Error())
+		return
+	}
+
+	if movesIndex != -1 {
+		for _, moveString := range args[movesIndex+1:] {
+			move, err := gobotcore.ParseMove(moveString)
+			if err == nil {
+				err = game.MakeMove(move)
+			}
+			if err != nil {
+				engine.writeLine("info string bad move " + moveString + ": " + err.Error())
+				break
+			}
+		}
+	}
+	engine.game = game
+}
+
+func (engine *Engine) goSearch(args []string) {
+	limits := gobotcore.SearchLimits{}
+	var times [2]time.Duration // Remaining clock, indexed by player
+	var increments [2]time.Duration
+	infinite := false
+	ponder := false
+
+	for i := 0; i < len(args); i++ {
+		value := 0
+		if i+1 < len(args) {
+			value, _ = strconv.Atoi(args[i+1])
+		}
+		switch args[i] {
+		case "infinite":
+			infinite = true
+			continue
+		case "ponder":
+			ponder = true
+			continue
+		case "depth":
+			// The search never goes deeper, and anything bigger would overflow the int8
+			if value > int(gobotcore.MaxSearchDepth) {
+				value = int(gobotcore.MaxSearchDepth)
+			}
+			if value > 0 {
+				limits.Depth = int8(value)
+			}
+		case "nodes":
+			limits.Nodes = int64(value)
+		case "movetime":
+			limits.MoveTime = time.Duration(value) * time.Millisecond
+		case "wtime":
+			times[gobotcore.HUMAN] = time.Duration(value) * time.Millisecond
+		case "btime":
+			times[gobotcore.GOBOT] = time.Duration(value) * time.Millisecond
+		case "winc":
+			increments[gobotcore.HUMAN] = time.Duration(value) * time.Millisecond
+		case "binc":
+			increments[gobotcore.GOBOT] = time.Duration(value) * time.Millisecond
+		case "movestogo":
+			limits.MovesToGo = value
+		case "searchmoves":
+			for _, moveString := range args[i+1:] {
+				move, err := gobotcore.ParseMove(moveString)
+				if err != nil {
+					engine.writeLine("info string bad move " + moveString)
+					continue
+				}
+				limits.SearchMoves = append(limits.SearchMoves, move)
+			}
+			i = len(args)
+			continue
+		default:
+			continue
+		}
+		i++ // Skip the value
+	}
+
+	// The search decides how much of the clock to use
+	player := engine.game.Turn()
+	limits.Clock = times[player]
+	limits.Increment = increments[player]
+	if limits.MoveTime == 0 && limits.Clock == 0 && limits.Depth == 0 && limits.Nodes == 0 && !infinite {
+		limits.MoveTime = engine.moveTime
+	}
+
+	board := engine.game.Board()
+	options := engine.options
+	stop := make(chan struct{})
+	engine.stop = stop
+	engine.searching.Add(1)
+	var search func() gobotcore.ScoredMove
+	var hit chan struct{}
+	if ponder {
+		engine.ponder = board.StartPonder(&player, limits, options, stop, engine.writeInfo)
+		hit = make(chan struct{})
+		engine.ponderHit = hit
+		search = engine.ponder.Wait
+	} else {
+		search = func() gobotcore.ScoredMove {
+			return board.SearchWithOptions(&player, limits, options, stop, engine.writeInfo)
+		}
+	}
+	go func() {
+		defer engine.searching.Done()
+		best := search()
+		// A search that ended on its own still may not report its move before stop, or ponderhit for a ponder
+		if infinite {
+			<-stop
+		} else if ponder {
+			select {
+			case <-stop:
+			case <-hit:
+			}
+		}
+		if len(best.PV()) == 0 {
+			engine.writeLine("bestmove none")
+			return
+		}
+		engine.writeLine("bestmove " + best.Move().ToString())
+	}()
+}
+
+func (engine *Engine) stopSearch() {
+	if engine.stop != nil {
+		close(engine.stop)
+		engine.stop = nil
+	}
+	engine.ponder = nil
+	engine.ponderHit = nil
+	engine.searching.Wait()
+}
+
+func (engine *Engine) setOption(args []string) {
+	nameIndex := indexOf(args, "name")
+	valueIndex := indexOf(args, "value")
+	if nameIndex == -1 || valueIndex < nameIndex {
+		engine.writeLine("info string setoption needs a name and a value")
+		return
+	}
+	name := strings.Join(args[nameIndex+1:valueIndex], " ")
+	value := strings.Join(args[valueIndex+1:], " ")
+
+	switch strings.ToLower(name) {
+	case "movetime":
+		ms, err := strconv.Atoi(value)
+		if err != nil || ms <= 0 {
+			engine.writeLine("info string MoveTime must be a positive number of milliseconds")
+			return
+		}
+		engine.moveTime = time.Duration(ms) * time.Millisecond
+	case "multipv":
+		lines, err := strconv.Atoi(value)
+		if err != nil || lines < 1 || lines > maxMultiPV {
+			engine.writeLine(fmt.Sprintf("info string MultiPV must be a number from 1 to %d", maxMultiPV))
+			return
+		}
+		engine.options.MultiPV = lines
+	case "nullmove":
+		engine.setCheck(name, value, &engine.options.NoNullMove)
+	case "latemovereductions":
+		engine.setCheck(name, value, &engine.options.NoLateMoveReductions)
+	case "futility":
+		engine.setCheck(name, value, &engine.options.NoFutility)
+	case "quiescence":
+		engine.setCheck(name, value, &engine.options.NoQuiescence)
+	case "deterministic":
+		random := !engine.options.Deterministic
+		engine.setCheck(name, value, &random)
+		engine.options.Deterministic = !random
+	case "evaluator":
+		network, err := gobotcore.ParseEvaluator(value)
+		if err != nil {
+			engine.writeLine("info string Evaluator must be classic or a network weights file: " + err.Error())
+			return
+		}
+		engine.options.Network = network
+	case "variant":
+		variant, err := gobotcore.ParseVariant(value)
+		if err == nil {
+			engine.stopSearch()
+			err = gobotcore.SetVariant(variant)
+		}
+		if err != nil {
+			engine.writeLine("info string " + err.Error())
+			return
+		}
+		engine.game = gobotcore.NewGame(gobotcore.HUMAN)
+		if engine.options.Network != nil {
+			// Its inputs are the squares of the old board
+			engine.options.Network = nil
+			engine.writeLine("info string Evaluator is back to classic, the network was trained on another board")
+		}
+	case "ponder":
+		// The GUI decides when to send go ponder, so there is nothing to change
+	default:
+		engine.writeLine("info string unknown option " + name)
+	}
+}
+
+func variantChoices() string {
+	choices := ""
+	for _, variant := range gobotcore.Variants {
+		choices += " var " + variant.Name
+	}
+	return choices
+}
+
+// Sets a check option. The search options turn features off, so off is stored as true
+func (engine *Engine) setCheck(name string, value string, off *bool) {
+	on, err := strconv.ParseBool(value)
+	if err != nil {
+		engine.writeLine("info string " + name + " must be true or false")
+		return
+	}
+	*off = !on
+}
+
+func (engine *Engine) writeInfo(info gobotcore.SearchInfo) {
+	if len(info.Lines) < 2 {
+		engine.writeLine(fmt.Sprintf("info depth %d score %s nodes %d time %d pv %s",
+			info.Depth, formatScore(info.Score), info.Nodes, info.Time/time.Millisecond, info.PV.ToString()))
+		return
+	}
+	for i, line := range info.Lines {
+		engine.writeLine(fmt.Sprintf("info depth %d multipv %d score %s nodes %d time %d pv %s",
+			info.Depth, i+1, formatScore(*line.Score()), info.Nodes, info.Time/time.Millisecond, line.PV().ToString()))
+	}
+}
+
+func formatScore(score float32) string {
+	if moves := gobotcore.MovesToWin(score); moves != 0 {
+		return fmt.Sprintf("mate %d", moves)
+	}
+	return fmt.Sprintf("cp %d", int(score*100))
+}
+
+func (engine *Engine) writeLine(line string) {
+	engine.outMu.Lock()
+	defer engine.outMu.Unlock()
+	fmt.Fprintln(engine.out, line)
+}
+
+func indexOf(strs []string, str string) int {
+	for i, cur := range strs {
+		if cur == str {
+			return i
+		}
+	}
+	return -1
+}
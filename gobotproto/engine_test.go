@@ -0,0 +1,299 @@
+package gobotproto
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEngine_Handshake(t *testing.T) {
+	var out bytes.Buffer
+	engine := NewEngine(&out)
+	err := engine.Run(strings.NewReader("morph\nisready\nquit\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if lines[0] != "id name Gobot" {
+		t.Error("Should identify itself first, got " + lines[0])
+	}
+	if lines[len(lines)-2] != "morphok" || lines[len(lines)-1] != "readyok" {
+		t.Error("Should end with morphok and readyok, got " + out.String())
+	}
+}
+
+func TestEngine_GoDepth(t *testing.T) {
+	var out bytes.Buffer
+	engine := NewEngine(&out)
+	engine.Handle("position startpos moves C3C4")
+	engine.Handle("go depth 3")
+	engine.Wait()
+
+	output := out.String()
+	if !strings.Contains(output, "info depth 3 ") {
+		t.Error("Should report depth 3, got " + output)
+	}
+	if strings.Contains(output, "info depth 4 ") {
+		t.Error("Should not search past depth 3")
+	}
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	bestMove := lines[len(lines)-1]
+	if !strings.HasPrefix(bestMove, "bestmove ") {
+		t.Fatal("Should end with bestmove, got " + bestMove)
+	}
+	// Gobot is to move, so the best move has to start on one of its pieces in the top rows
+	if row := bestMove[len(bestMove)-3]; row < '5' {
+		t.Error("Best move should be a Gobot move, got " + bestMove)
+	}
+}
+
+func TestEngine_Stop(t *testing.T) {
+	var out bytes.Buffer
+	engine := NewEngine(&out)
+	engine.Handle("position startpos")
+	engine.Handle("go infinite")
+	time.Sleep(100 * time.Millisecond)
+	engine.Handle("stop")
+
+	if !strings.Contains(out.String(), "bestmove ") {
+		t.Error("Should report a best move after stop, got " + out.String())
+	}
+}
+
+func TestEngine_BadMove(t *testing.T) {
+	var out bytes.Buffer
+	engine := NewEngine(&out)
+	engine.Handle("position startpos moves C3C5")
+	if !strings.Contains(out.String(), "info string bad move C3C5") {
+		t.Error("Should complain about the illegal move, got " + out.String())
+	}
+}
+
+func TestEngine_PruningOptions(t *testing.T) {
+	var out bytes.Buffer
+	engine := NewEngine(&out)
+	engine.Handle("setoption name NullMove value false")
+	engine.Handle("setoption name Futility value false")
+	engine.Handle("setoption name Quiescence value false")
+	if !engine.options.NoNullMove || !engine.options.NoFutility || !engine.options.NoQuiescence || engine.options.NoLateMoveReductions {
+		t.Errorf("Only null move, futility pruning and quiescence should be off, got %+v", engine.options)
+	}
+	engine.Handle("setoption name NullMove value true")
+	if engine.options.NoNullMove {
+		t.Error("Null move should be back on")
+	}
+	engine.Handle("setoption name LateMoveReductions value maybe")
+	if !strings.Contains(out.String(), "info string LateMoveReductions must be true or false") {
+		t.Error("Should complain about the bad value, got " + out.String())
+	}
+}
+
+func TestEngine_GoClock(t *testing.T) {
+	var out bytes.Buffer
+	engine := NewEngine(&out)
+	engine.Handle("position startpos")
+	start := time.Now()
+	engine.Handle("go wtime 3000 btime 60000 movestogo 10")
+	engine.Wait()
+	// Human is to move, so only its 3 seconds count
+	if elapsed := time.Since(start); elapsed > time.Second+100*time.Millisecond {
+		t.Error("Should use at most a third of Human's clock, took", elapsed)
+	}
+	if !strings.Contains(out.String(), "bestmove ") {
+		t.Error("Should report a best move, got " + out.String())
+	}
+}
+
+func TestEngine_ScoreMate(t *testing.T) {
+	var out bytes.Buffer
+	engine := NewEngine(&out)
+	// Gobot's bishop takes the king
+	engine.Handle("position fen 6/6/6/6/6/6/2B3/1r1k2 g")
+	engine.Handle("go depth 2")
+	engine.Wait()
+	if !strings.Contains(out.String(), "info depth 2 score mate 1 ") {
+		t.Error("Should report a win in 1, got " + out.String())
+	}
+}
+
+func TestEngine_Ponder(t *testing.T) {
+	var out bytes.Buffer
+	engine := NewEngine(&out)
+	engine.Handle("position startpos moves C3C4 D6D5")
+	engine.Handle("go ponder movetime 100")
+	time.Sleep(300 * time.Millisecond)
+	if strings.Contains(out.String(), "bestmove") {
+		t.Fatal("Should keep pondering until ponderhit, got " + out.String())
+	}
+	start := time.Now()
+	engine.Handle("ponderhit")
+	engine.Wait()
+	if elapsed := time.Since(start); elapsed > 250*time.Millisecond {
+		t.Error("The move time should count from ponderhit, took", elapsed)
+	}
+	if !strings.Contains(out.String(), "bestmove ") {
+		t.Error("Should report a best move after ponderhit, got " + out.String())
+	}
+}
+
+func TestEngine_PonderStop(t *testing.T) {
+	var out bytes.Buffer
+	engine := NewEngine(&out)
+	engine.Handle("position startpos moves C3C4 D6D5")
+	engine.Handle("go ponder movetime 100")
+	time.Sleep(50 * time.Millisecond)
+	engine.Handle("stop")
+	if !strings.Contains(out.String(), "bestmove ") {
+		t.Error("Should report a best move when a ponder is stopped, got " + out.String())
+	}
+}
+
+func TestEngine_MultiPV(t *testing.T) {
+	var out bytes.Buffer
+	engine := NewEngine(&out)
+	engine.Handle("setoption name MultiPV value 3")
+	engine.Handle("position startpos")
+	engine.Handle("go depth 2")
+	engine.Wait()
+	for _, line := range []string{"info depth 2 multipv 1 ", "info depth 2 multipv 2 ", "info depth 2 multipv 3 "} {
+		if !strings.Contains(out.String(), line) {
+			t.Error("Should report 3 lines at depth 2, got " + out.String())
+		}
+	}
+	engine.Handle("setoption name MultiPV value 0")
+	if !strings.Contains(out.String(), "info string MultiPV must be a number from 1 to") {
+		t.Error("Should complain about the bad value, got " + out.String())
+	}
+}
+
+func TestEngine_SearchMoves(t *testing.T) {
+	var out bytes.Buffer
+	engine := NewEngine(&out)
+	engine.Handle("position startpos")
+	engine.Handle("go depth 3 searchmoves C3C4")
+	engine.Wait()
+	if !strings.HasSuffix(out.String(), "bestmove C3C4\n") {
+		t.Error("Should only search C3C4, got " + out.String())
+	}
+}
+
+func TestEngine_Deterministic(t *testing.T) {
+	var outputs [2]string
+	for i := range outputs {
+		var out bytes.Buffer
+		engine := NewEngine(&out)
+		engine.Handle("setoption name Deterministic value true")
+		engine.Handle("position startpos moves C3C4")
+		engine.Handle("go nodes 20000")
+		engine.Wait()
+		// Only the times may differ
+		for _, line := range strings.Split(out.String(), "\n") {
+			fields := strings.Fields(line)
+			if timeIndex := indexOf(fields, "time"); timeIndex != -1 {
+				fields = append(fields[:timeIndex], fields[timeIndex+2:]...)
+			}
+			outputs[i] += strings.Join(fields, " ") + "\n"
+		}
+	}
+	if outputs[0] != outputs[1] {
+		t.Error("Should give the same output twice, got\n" + outputs[0] + "and\n" + outputs[1])
+	}
+}
+
+func TestEngine_Evaluator(t *testing.T) {
+	var out bytes.Buffer
+	engine := NewEngine(&out)
+	engine.Handle("setoption name Evaluator value no-such-weights.gbnn")
+	if engine.options.Network != nil || !strings.Contains(out.String(), "info string Evaluator must be classic or a network weights file") {
+		t.Error("A missing weights file should be reported, got " + out.String())
+	}
+	engine.Handle("setoption name Evaluator value classic")
+	if engine.options.Network != nil {
+		t.Error("classic should be the hand written evaluation")
+	}
+}
+
+func TestEngine_Variant(t *testing.T) {
+	var out bytes.Buffer
+	engine := NewEngine(&out)
+	defer engine.Handle("setoption name Variant value standard")
+	engine.Handle("setoption name Variant value huge")
+	if !strings.Contains(out.String(), "info string variant must be one of standard, mini, large") {
+		t.Error("An unknown variant should be reported, got " + out.String())
+	}
+	engine.Handle("setoption name Variant value mini")
+	engine.Handle("position startpos")
+	engine.Handle("go depth 3")
+	engine.Wait()
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	bestMove := strings.TrimPrefix(lines[len(lines)-1], "bestmove ")
+	if len(bestMove) != 4 || bestMove[0] > 'E' || bestMove[2] > 'E' || bestMove[1] > '6' || bestMove[3] > '6' {
+		t.Error("The best move should be on the 5x6 board, got " + bestMove)
+	}
+}
+
+func TestEngine_HoldsBestMove(t *testing.T) {
+	var out bytes.Buffer
+	engine := NewEngine(&out)
+	engine.Handle("position startpos")
+	engine.Handle("go infinite depth 2")
+	// The search is done after depth 2, but the move has to wait for stop
+	if !waitForOutput(engine, &out, "info depth 2 ", 5*time.Second) {
+		t.Fatal("Should search to depth 2, got " + output(engine, &out))
+	}
+	if waitForOutput(engine, &out, "bestmove", 100*time.Millisecond) {
+		t.Fatal("go infinite should wait for stop, got " + output(engine, &out))
+	}
+	engine.Handle("stop")
+	if !strings.Contains(output(engine, &out), "bestmove ") {
+		t.Error("Should report a best move after stop, got " + output(engine, &out))
+	}
+
+	out.Reset()
+	engine.Handle("position startpos moves C3C4 D6D5")
+	engine.Handle("go ponder depth 2")
+	if !waitForOutput(engine, &out, "info depth 2 ", 5*time.Second) {
+		t.Fatal("Should ponder to depth 2, got " + output(engine, &out))
+	}
+	if waitForOutput(engine, &out, "bestmove", 100*time.Millisecond) {
+		t.Fatal("go ponder should wait for ponderhit, got " + output(engine, &out))
+	}
+	engine.Handle("ponderhit")
+	engine.Wait()
+	if !strings.Contains(output(engine, &out), "bestmove ") {
+		t.Error("Should report a best move after ponderhit, got " + output(engine, &out))
+	}
+}
+
+func TestEngine_DepthRange(t *testing.T) {
+	var out bytes.Buffer
+	engine := NewEngine(&out)
+	engine.Handle("position startpos")
+	// 257 would wrap around to a depth of 1 in an int8, which is over before depth 2
+	engine.Handle("go depth 257")
+	if !waitForOutput(engine, &out, "info depth 2 ", 5*time.Second) {
+		t.Error("A depth past the deepest search should search as deep as it can, got " + output(engine, &out))
+	}
+	engine.Handle("stop")
+}
+
+// The output so far, read under the engine's lock since a search may still be writing to it
+func output(engine *Engine, out *bytes.Buffer) string {
+	engine.outMu.Lock()
+	defer engine.outMu.Unlock()
+	return out.String()
+}
+
+// Waits until the engine has written text, for at most timeout. Returns whether it did
+func waitForOutput(engine *Engine, out *bytes.Buffer, text string, timeout time.Duration) bool {
+	deadline := time.Now().Add(timeout)
+	for !strings.Contains(output(engine, out), text) {
+		if time.Now().After(deadline) {
+			return false
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	return true
+}
@@ -0,0 +1,579 @@
+/* Package gobotserver exposes Gobot over a small JSON API so it can be embedded in other tools.
+ *
+ *	POST   /api/legal-moves           {"position"}                          -> {"moves"}
+ *	POST   /api/make-move             {"position", "move"}                  -> game
+ *	POST   /api/analyze               {"position", "depth", "moveTimeMs"}   -> analysis
+ *	                                  Also takes "multiPV" and "searchMoves", see below
+ *	POST   /api/games                 {"position"} (optional)               -> game
+ *	GET    /api/games/{id}                                                  -> game
+ *	DELETE /api/games/{id}
+ *	POST   /api/games/{id}/move       {"move"}                              -> game
+ *	POST   /api/games/{id}/engine     {"depth", "moveTimeMs"}               -> {"analysis", "game"}
+ *	POST   /api/games/{id}/undo                                             -> game
+ *	GET    /api/games/{id}/events     Server-sent events: "state" with the game, "info" while the engine thinks
+ *	GET    /                          The browser UI
+ *
+ * Positions use the format from gobotcore.ParsePosition and moves are written like "C3C4".
+ * Every session plays the variant the server was started with (gobot serve -variant). There is no way to change it
+ * while the server runs, since the board size is shared by the whole program and the sessions search at the same time.
+ * With a multiPV above 1 the analysis also lists that many best moves in "lines", and searchMoves limits the search to the given moves.
+ * Both work for the engine endpoint too.
+ * Errors are returned as {"error": "..."} with a 4xx status.
+ */
+package gobotserver
+
+import (
+	"crypto/rand"
+	"embed"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ktodaz/gobot/gobotcore"
+)
+
+const (
+	defaultMoveTime = time.Second
+	maxMoveTime     = time.Minute
+	maxMultiPV      = 32
+)
+
+var errNotFound = errors.New("no such game")
+
+//go:embed web
+var webFiles embed.FS
+
+type Server struct {
+	mu       sync.Mutex
+	sessions map[string]*session
+	web      http.Handler
+}
+
+// Every session has its own lock, so games don't wait on each other
+type session struct {
+	mu   sync.Mutex
+	game *gobotcore.Game
+
+	subscribersMu sync.Mutex
+	subscribers   map[chan event]struct{}
+}
+
+// A server-sent event
+type event struct {
+	name string
+	data []byte
+}
+
+func NewServer() *Server {
+	web, err := fs.Sub(webFiles, "web")
+	if err != nil {
+		panic(err)
+	}
+	return &Server{sessions: map[string]*session{}, web: http.FileServer(http.FS(web))}
+}
+
+// ================== JSON types ==================
+
+type request struct {
+	Position   string `json:"position"`
+	Move       string `json:"move"`
+	Depth      int8   `json:"depth"`
+	MoveTimeMs int    `json:"moveTimeMs"`
+
+	MultiPV     int      `json:"multiPV"`
+	SearchMoves []string `json:"searchMoves"`
+}
+
+type GameState struct {
+	ID         string     `json:"id,omitempty"`
+	Position   string     `json:"position"`
+	Turn       string     `json:"turn"`
+	LegalMoves []string   `json:"legalMoves"`
+	History    []string   `json:"history"`
+	Played     []MoveInfo `json:"played"`
+	Over       bool       `json:"over"`
+	Winner     string     `json:"winner,omitempty"`
+}
+
+// Pieces are written with their names from gobotcore.Piece.GetName
+type MoveInfo struct {
+	Move   string `json:"move"`
+	Player string `json:"player"`
+	Piece  string `json:"piece"`
+	Became string `json:"became"`
+	Taken  string `json:"taken,omitempty"`
+}
+
+type Analysis struct {
+	BestMove string   `json:"bestMove"`
+	Score    float32  `json:"score"`
+	Depth    int8     `json:"depth"`
+	Nodes    int64    `json:"nodes"`
+	TimeMs   int64    `json:"timeMs"`
+	PV       []string `json:"pv"`
+
+	// Moves the side to move needs to capture the king, negative if it loses. Omitted if nobody is winning yet
+	MovesToWin int `json:"movesToWin,omitempty"`
+
+	// The best moves with their own scores, best first. Only with a multiPV above 1
+	Lines []Line `json:"lines,omitempty"`
+}
+
+type Line struct {
+	Move       string   `json:"move"`
+	Score      float32  `json:"score"`
+	MovesToWin int      `json:"movesToWin,omitempty"`
+	PV         []string `json:"pv"`
+}
+
+type engineMoveResponse struct {
+	Analysis Analysis  `json:"analysis"`
+	Game     GameState `json:"game"`
+}
+
+// ================== Routing ==================
+
+func (server *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	path := strings.Trim(r.URL.Path, "/")
+	parts := strings.Split(path, "/")
+	if parts[0] != "api" {
+		server.web.ServeHTTP(w, r)
+		return
+	}
+	if len(parts) < 2 {
+		writeError(w, http.StatusNotFound, errors.New("unknown endpoint"))
+		return
+	}
+
+	switch {
+	case path == "api/legal-moves" && r.Method == http.MethodPost:
+		server.legalMoves(w, r)
+	case path == "api/make-move" && r.Method == http.MethodPost:
+		server.makeMove(w, r)
+	case path == "api/analyze" && r.Method == http.MethodPost:
+		server.analyze(w, r)
+	case path == "api/games" && r.Method == http.MethodPost:
+		server.newGame(w, r)
+	case parts[1] == "games" && len(parts) == 3 && r.Method == http.MethodGet:
+		server.getGame(w, parts[2])
+	case parts[1] == "games" && len(parts) == 3 && r.Method == http.MethodDelete:
+		server.deleteGame(w, parts[2])
+	case parts[1] == "games" && len(parts) == 4 && parts[3] == "events" && r.Method == http.MethodGet:
+		server.events(w, r, parts[2])
+	case parts[1] == "games" && len(parts) == 4 && r.Method == http.MethodPost:
+		server.gameAction(w, r, parts[2], parts[3])
+	default:
+		writeError(w, http.StatusNotFound, errors.New("unknown endpoint"))
+	}
+}
+
+// ================== Stateless endpoints ==================
+
+func (server *Server) legalMoves(w http.ResponseWriter, r *http.Request) {
+	game, _, ok := readGame(w, r)
+	if !ok {
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string][]string{"moves": moveStrings(game.LegalMoves())})
+}
+
+func (server *Server) makeMove(w http.ResponseWriter, r *http.Request) {
+	game, req, ok := readGame(w, r)
+	if !ok {
+		return
+	}
+	if err := playMove(game, req.Move); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, stateOf("", game))
+}
+
+func (server *Server) analyze(w http.ResponseWriter, r *http.Request) {
+	game, req, ok := readGame(w, r)
+	if !ok {
+		return
+	}
+	analysis, err := search(game.Board(), game.Turn(), req, r.Context().Done(), nil)
+	if r.Context().Err() != nil {
+		return // The client went away, so there is no one to answer
+	}
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, analysis)
+}
+
+// ================== Sessions ==================
+
+func (server *Server) newGame(w http.ResponseWriter, r *http.Request) {
+	game, _, ok := readGame(w, r)
+	if !ok {
+		return
+	}
+	id, err := newID()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	server.mu.Lock()
+	server.sessions[id] = &session{game: game, subscribers: map[chan event]struct{}{}}
+	server.mu.Unlock()
+	writeJSON(w, http.StatusCreated, stateOf(id, game))
+}
+
+func (server *Server) getGame(w http.ResponseWriter, id string) {
+	session, err := server.session(id)
+	if err != nil {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+	session.mu.Lock()
+	defer session.mu.Unlock()
+	writeJSON(w, http.StatusOK, stateOf(id, session.game))
+}
+
+func (server *Server) deleteGame(w http.ResponseWriter, id string) {
+	server.mu.Lock()
+	_, ok := server.sessions[id]
+	delete(server.sessions, id)
+	server.mu.Unlock()
+	if !ok {
+		writeError(w, http.StatusNotFound, errNotFound)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (server *Server) gameAction(w http.ResponseWriter, r *http.Request, id string, action string) {
+	session, err := server.session(id)
+	if err != nil {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+	var req request
+	if !readRequest(w, r, &req) {
+		return
+	}
+
+	switch action {
+	case "move":
+		session.mu.Lock()
+		defer session.mu.Unlock()
+		if err := playMove(session.game, req.Move); err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, session.publishState(id))
+	case "undo":
+		session.mu.Lock()
+		defer session.mu.Unlock()
+		if _, err := session.game.Undo(); err != nil {
+			writeError(w, http.StatusConflict, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, session.publishState(id))
+	case "engine":
+		server.engineMove(w, r, id, session, req)
+	default:
+		writeError(w, http.StatusNotFound, errors.New("unknown endpoint"))
+	}
+}
+
+// The search runs without holding the session lock, so the game can still be read while the engine thinks
+func (server *Server) engineMove(w http.ResponseWriter, r *http.Request, id string, session *session, req request) {
+	session.mu.Lock()
+	if session.game.IsOver() {
+		session.mu.Unlock()
+		writeError(w, http.StatusConflict, gobotcore.ErrGameOver)
+		return
+	}
+	board := session.game.Board()
+	turn := session.game.Turn()
+	// An undo and another move keep the number of moves, so the position itself tells whether the game changed
+	position := session.game.Position()
+	session.mu.Unlock()
+
+	analysis, err := search(board, turn, req, r.Context().Done(), func(info gobotcore.SearchInfo) {
+		session.publish("info", analysisOf(info))
+	})
+	if r.Context().Err() != nil {
+		return // The client gave up on the move, so the cut short search isn't played
+	}
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	session.mu.Lock()
+	defer session.mu.Unlock()
+	if session.game.Position() != position {
+		writeError(w, http.StatusConflict, errors.New("game changed while the engine was thinking"))
+		return
+	}
+	if err := playMove(session.game, analysis.BestMove); err != nil {
+		writeError(w, http.StatusConflict, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, engineMoveResponse{Analysis: analysis, Game: session.publishState(id)})
+}
+
+// Streams the game's state and the engine's thinking until the client goes away
+func (server *Server) events(w http.ResponseWriter, r *http.Request, id string) {
+	session, err := server.session(id)
+	if err != nil {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, http.StatusInternalServerError, errors.New("streaming is not supported"))
+		return
+	}
+
+	events := session.subscribe()
+	defer session.unsubscribe(events)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	session.mu.Lock()
+	state, _ := json.Marshal(stateOf(id, session.game))
+	session.mu.Unlock()
+	fmt.Fprintf(w, "event: state\ndata: %s\n\n", state)
+	flusher.Flush()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case event := <-events:
+			fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event.name, event.data)
+			flusher.Flush()
+		}
+	}
+}
+
+func (session *session) subscribe() chan event {
+	events := make(chan event, 16)
+	session.subscribersMu.Lock()
+	session.subscribers[events] = struct{}{}
+	session.subscribersMu.Unlock()
+	return events
+}
+
+func (session *session) unsubscribe(events chan event) {
+	session.subscribersMu.Lock()
+	delete(session.subscribers, events)
+	session.subscribersMu.Unlock()
+}
+
+// Slow subscribers miss events rather than holding up the game
+func (session *session) publish(name string, value interface{}) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return
+	}
+	session.subscribersMu.Lock()
+	defer session.subscribersMu.Unlock()
+	for events := range session.subscribers {
+		select {
+		case events <- event{name: name, data: data}:
+		default:
+		}
+	}
+}
+
+// Sends the game to every subscriber and returns it. The caller must hold session.mu
+func (session *session) publishState(id string) GameState {
+	state := stateOf(id, session.game)
+	session.publish("state", state)
+	return state
+}
+
+func (server *Server) session(id string) (*session, error) {
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	session, ok := server.sessions[id]
+	if !ok {
+		return nil, errNotFound
+	}
+	return session, nil
+}
+
+// ================== Helpers ==================
+
+// The search ends early when stop is closed, e.g. when the client disconnects. info is called after every completed depth and may be nil
+func search(board gobotcore.Board, player gobotcore.Player, req request, stop <-chan struct{}, info func(gobotcore.SearchInfo)) (Analysis, error) {
+	limits := gobotcore.SearchLimits{Depth: req.Depth, MoveTime: time.Duration(req.MoveTimeMs) * time.Millisecond}
+	if limits.MoveTime > maxMoveTime || (limits.MoveTime <= 0 && limits.Depth <= 0) {
+		limits.MoveTime = defaultMoveTime
+	}
+	if limits.Depth > 0 && limits.MoveTime <= 0 {
+		limits.MoveTime = maxMoveTime
+	}
+	for _, moveString := range req.SearchMoves {
+		move, err := gobotcore.ParseMove(moveString)
+		if err != nil {
+			return Analysis{}, err
+		}
+		limits.SearchMoves = append(limits.SearchMoves, move)
+	}
+	if req.MultiPV < 0 || req.MultiPV > maxMultiPV {
+		return Analysis{}, fmt.Errorf("multiPV must be from 1 to %d", maxMultiPV)
+	}
+	options := gobotcore.SearchOptions{MultiPV: req.MultiPV}
+
+	var last gobotcore.SearchInfo
+	lines := board.SearchLines(&player, limits, options, stop, func(cur gobotcore.SearchInfo) {
+		last = cur
+		if info != nil {
+			info(cur)
+		}
+	})
+
+	analysis := analysisOf(last)
+	analysis.Lines = linesOf(lines)
+	if len(lines) > 0 {
+		best := lines[0]
+		analysis.Score = *best.Score()
+		analysis.MovesToWin = gobotcore.MovesToWin(analysis.Score)
+		analysis.PV = moveStrings(best.PV())
+		analysis.BestMove = best.Move().ToString()
+	}
+	return analysis, nil
+}
+
+func analysisOf(info gobotcore.SearchInfo) Analysis {
+	analysis := Analysis{
+		Score:  info.Score,
+		Depth:  info.Depth,
+		Nodes:  info.Nodes,
+		TimeMs: int64(info.Time / time.Millisecond),
+		PV:     moveStrings(info.PV),
+	}
+	analysis.MovesToWin = gobotcore.MovesToWin(info.Score)
+	analysis.Lines = linesOf(info.Lines)
+	if len(info.PV) > 0 {
+		analysis.BestMove = info.PV[0].ToString()
+	}
+	return analysis
+}
+
+// Only a search for more than one line lists them
+func linesOf(scored []gobotcore.ScoredMove) []Line {
+	if len(scored) < 2 {
+		return nil
+	}
+	lines := make([]Line, len(scored))
+	for i, line := range scored {
+		lines[i] = Line{
+			Move:       line.Move().ToString(),
+			Score:      *line.Score(),
+			MovesToWin: gobotcore.MovesToWin(*line.Score()),
+			PV:         moveStrings(line.PV()),
+		}
+	}
+	return lines
+}
+
+func playMove(game *gobotcore.Game, moveString string) error {
+	move, err := gobotcore.ParseMove(moveString)
+	if err != nil {
+		return err
+	}
+	return game.MakeMove(move)
+}
+
+func stateOf(id string, game *gobotcore.Game) GameState {
+	state := GameState{
+		ID:         id,
+		Position:   game.Position(),
+		Turn:       strings.ToLower(game.Turn().Name()),
+		LegalMoves: moveStrings(game.LegalMoves()),
+		History:    []string{},
+		Played:     []MoveInfo{},
+	}
+	for _, played := range game.History() {
+		state.History = append(state.History, played.Move.ToString())
+		became := played.Piece.Morph()
+		info := MoveInfo{
+			Move:   played.Move.ToString(),
+			Player: strings.ToLower(played.Player.Name()),
+			Piece:  played.Piece.GetName(),
+			Became: became.GetName(),
+		}
+		if !played.TakenPiece.IsEmpty() {
+			info.Taken = played.TakenPiece.GetName()
+		}
+		state.Played = append(state.Played, info)
+	}
+	if winner, over := game.Winner(); over {
+		state.Over = true
+		state.Winner = strings.ToLower(winner.Name())
+		state.LegalMoves = []string{}
+	}
+	return state
+}
+
+func moveStrings(moves gobotcore.Moves) []string {
+	strs := make([]string, len(moves))
+	for i, move := range moves {
+		strs[i] = move.ToString()
+	}
+	return strs
+}
+
+// Reads a request and builds a game from its position, which defaults to the start position
+func readGame(w http.ResponseWriter, r *http.Request) (*gobotcore.Game, request, bool) {
+	var req request
+	if !readRequest(w, r, &req) {
+		return nil, req, false
+	}
+	if req.Position == "" {
+		req.Position = gobotcore.CurrentVariant().Start
+	}
+	game, err := gobotcore.NewGameFromPosition(req.Position)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return nil, req, false
+	}
+	return game, req, true
+}
+
+// An empty body is the same as an empty request
+func readRequest(w http.ResponseWriter, r *http.Request, req *request) bool {
+	if r.ContentLength == 0 {
+		return true
+	}
+	if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+		writeError(w, http.StatusBadRequest, errors.New("bad json: "+err.Error()))
+		return false
+	}
+	return true
+}
+
+func writeJSON(w http.ResponseWriter, status int, value interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(value)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
+
+func newID() (string, error) {
+	bytes := make([]byte, 8)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(bytes), nil
+}
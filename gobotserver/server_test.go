@@ -0,0 +1,234 @@
+package gobotserver
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func post(t *testing.T, handler http.Handler, path string, body string, value interface{}) int {
+	req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if value != nil {
+		if err := json.Unmarshal(rec.Body.Bytes(), value); err != nil {
+			t.Error("Bad response from " + path + ": " + rec.Body.String())
+		}
+	}
+	return rec.Code
+}
+
+func TestServer_LegalMoves(t *testing.T) {
+	server := NewServer()
+	var resp map[string][]string
+	status := post(t, server, "/api/legal-moves", `{"position": "K5/6/6/6/6/6/6/4k1 h"}`, &resp)
+	if status != http.StatusOK {
+		t.Fatal("Should succeed")
+	}
+	if len(resp["moves"]) != 1 || resp["moves"][0] != "E1D1" {
+		t.Error("King can only move left")
+	}
+
+	var errResp map[string]string
+	if post(t, server, "/api/legal-moves", `{"position": "nonsense"}`, &errResp) != http.StatusBadRequest || errResp["error"] == "" {
+		t.Error("Bad positions should be rejected")
+	}
+}
+
+func TestServer_MakeMove(t *testing.T) {
+	server := NewServer()
+	var state GameState
+	if post(t, server, "/api/make-move", `{"move": "C3C4"}`, &state) != http.StatusOK {
+		t.Fatal("Should succeed")
+	}
+	if state.Turn != "gobot" || state.Position != "1K4/NBRRBN/2PP2/6/2p3/3p2/nbrrbn/4k1 g" {
+		t.Error("Wrong position after move: " + state.Position)
+	}
+	if post(t, server, "/api/make-move", `{"move": "C3C5"}`, nil) != http.StatusBadRequest {
+		t.Error("Illegal moves should be rejected")
+	}
+}
+
+func TestServer_Analyze(t *testing.T) {
+	server := NewServer()
+	var analysis Analysis
+	post(t, server, "/api/analyze", `{"position": "6/6/6/6/6/6/2B3/1r1k2 g", "depth": 2}`, &analysis)
+	if analysis.BestMove != "C2D1" {
+		t.Error("Should take the king, got " + analysis.BestMove)
+	}
+	if analysis.Depth != 2 || len(analysis.PV) == 0 {
+		t.Error("Should report depth and pv")
+	}
+}
+
+func TestServer_AnalyzeCancelled(t *testing.T) {
+	server := NewServer()
+	ctx, cancel := context.WithCancel(context.Background())
+	req := httptest.NewRequest(http.MethodPost, "/api/analyze", strings.NewReader(`{"moveTimeMs": 30000}`)).WithContext(ctx)
+	rec := httptest.NewRecorder()
+	time.AfterFunc(50*time.Millisecond, cancel)
+	start := time.Now()
+	server.ServeHTTP(rec, req)
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Error("The search should stop when the client goes away, took", elapsed)
+	}
+}
+
+func TestServer_AnalyzeMultiPV(t *testing.T) {
+	server := NewServer()
+	var analysis Analysis
+	post(t, server, "/api/analyze", `{"depth": 2, "multiPV": 3, "searchMoves": ["C3C4", "D3D4", "A2B4", "F2E4"]}`, &analysis)
+	if len(analysis.Lines) != 3 {
+		t.Fatal("Should report 3 lines, got", analysis.Lines)
+	}
+	if analysis.Lines[0].Move != analysis.BestMove {
+		t.Error("The first line should be the best move, got " + analysis.Lines[0].Move)
+	}
+	for _, line := range analysis.Lines {
+		if line.Move != "C3C4" && line.Move != "D3D4" && line.Move != "A2B4" && line.Move != "F2E4" {
+			t.Error("Should only search the given moves, got " + line.Move)
+		}
+	}
+
+	if post(t, server, "/api/analyze", `{"depth": 2, "searchMoves": ["nonsense"]}`, nil) != http.StatusBadRequest {
+		t.Error("Bad moves should be rejected")
+	}
+}
+
+func TestServer_Sessions(t *testing.T) {
+	server := NewServer()
+	var wg sync.WaitGroup
+	ids := make([]string, 4)
+	for i := range ids {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			var state GameState
+			if post(t, server, "/api/games", "", &state) != http.StatusCreated {
+				t.Error("Should create a game")
+			}
+			ids[i] = state.ID
+			post(t, server, "/api/games/"+state.ID+"/move", `{"move": "C3C4"}`, &state)
+			var resp engineMoveResponse
+			if post(t, server, "/api/games/"+state.ID+"/engine", `{"depth": 2}`, &resp) != http.StatusOK {
+				t.Error("Engine should move")
+			}
+			if len(resp.Game.History) != 2 || resp.Game.Turn != "human" {
+				t.Error("Each game should only have its own two moves")
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	var state GameState
+	if post(t, server, "/api/games/"+ids[0]+"/undo", "", &state) != http.StatusOK || len(state.History) != 1 {
+		t.Error("Undo should take back the engine move")
+	}
+
+	req := httptest.NewRequest(http.MethodDelete, "/api/games/"+ids[0], nil)
+	rec := httptest.NewRecorder()
+	server.ServeHTTP(rec, req)
+	if rec.Code != http.StatusNoContent {
+		t.Error("Should delete the game")
+	}
+	if post(t, server, "/api/games/"+ids[0]+"/move", `{"move": "C4C5"}`, nil) != http.StatusNotFound {
+		t.Error("Deleted games should be gone")
+	}
+}
+
+func TestServer_EngineMoveStale(t *testing.T) {
+	server := NewServer()
+	var state GameState
+	post(t, server, "/api/games", "", &state)
+	id := state.ID
+	post(t, server, "/api/games/"+id+"/move", `{"move": "C3C4"}`, nil)
+	post(t, server, "/api/games/"+id+"/move", `{"move": "C6C5"}`, nil)
+	session, err := server.session(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	events := session.subscribe()
+	defer session.unsubscribe(events)
+
+	status := make(chan int, 1)
+	go func() {
+		status <- post(t, server, "/api/games/"+id+"/engine", `{"moveTimeMs": 1000}`, nil)
+	}()
+	// Once the engine reports a depth it is searching Human's move in the old position
+	deadline := time.After(5 * time.Second)
+	for searching := false; !searching; {
+		select {
+		case event := <-events:
+			searching = event.name == "info"
+		case <-deadline:
+			t.Fatal("The engine should report its search")
+		}
+	}
+	// Another Gobot move in place of C6C5 keeps the number of moves the same
+	if post(t, server, "/api/games/"+id+"/undo", "", nil) != http.StatusOK || post(t, server, "/api/games/"+id+"/move", `{"move": "B8C8"}`, nil) != http.StatusOK {
+		t.Fatal("Should take back C6C5 and play B8C8 instead")
+	}
+
+	if code := <-status; code != http.StatusConflict {
+		t.Error("A move searched in a position that was undone shouldn't be played, got status", code)
+	}
+}
+
+func TestServer_WebUI(t *testing.T) {
+	server := NewServer()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	rec := httptest.NewRecorder()
+	server.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), "<title>Gobot</title>") {
+		t.Error("Should serve the board UI")
+	}
+}
+
+func TestServer_Events(t *testing.T) {
+	server := NewServer()
+	httpServer := httptest.NewServer(server)
+	defer httpServer.Close()
+
+	var state GameState
+	post(t, server, "/api/games", `{"position": "K5/6/6/6/6/6/2B3/1r1k2 g"}`, &state)
+
+	resp, err := http.Get(httpServer.URL + "/api/games/" + state.ID + "/events")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	reader := bufio.NewReader(resp.Body)
+	readEvent := func() (string, string) {
+		name, _ := reader.ReadString('\n')
+		data, _ := reader.ReadString('\n')
+		reader.ReadString('\n')
+		return strings.TrimSpace(strings.TrimPrefix(name, "event:")), strings.TrimSpace(strings.TrimPrefix(data, "data:"))
+	}
+
+	if name, _ := readEvent(); name != "state" {
+		t.Fatal("Should start with the current state, got " + name)
+	}
+	go post(t, server, "/api/games/"+state.ID+"/engine", `{"depth": 2}`, nil)
+	sawInfo := false
+	for {
+		name, data := readEvent()
+		if name == "info" {
+			sawInfo = true
+		}
+		if name == "state" {
+			if !strings.Contains(data, `"winner":"gobot"`) {
+				t.Error("Gobot should have taken the king: " + data)
+			}
+			break
+		}
+	}
+	if !sawInfo {
+		t.Error("Should stream the engine's thinking")
+	}
+}
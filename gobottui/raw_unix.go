@@ -0,0 +1,30 @@
+//go:build !windows
+
+package gobottui
+
+import (
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// Puts the terminal into raw mode with stty and returns a function that puts it back
+func makeRaw(terminal *os.File) (func(), error) {
+	state, err := stty(terminal, "-g")
+	if err != nil {
+		return nil, err
+	}
+	if _, err := stty(terminal, "raw", "-echo"); err != nil {
+		return nil, err
+	}
+	return func() {
+		stty(terminal, strings.TrimSpace(state))
+	}, nil
+}
+
+func stty(terminal *os.File, args ...string) (string, error) {
+	cmd := exec.Command("stty", args...)
+	cmd.Stdin = terminal
+	out, err := cmd.Output()
+	return string(out), err
+}
@@ -0,0 +1,12 @@
+//go:build windows
+
+package gobottui
+
+import (
+	"errors"
+	"os"
+)
+
+func makeRaw(terminal *os.File) (func(), error) {
+	return nil, errors.New("the terminal UI is not supported on Windows yet")
+}
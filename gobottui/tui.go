@@ -0,0 +1,452 @@
+/* Package gobottui is a full screen terminal UI for playing against Gobot.
+ *
+ * The player moves a cursor over the board with the arrow keys (or h j k l), picks a piece with enter or space
+ * and then picks where it should go. Other keys:
+ *	u  take back your last move and Gobot's reply
+ *	?  ask Gobot for a hint
+ *	f  flip the board
+ *	s  save the game
+ *	q  quit
+ */
+package gobottui
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ktodaz/gobot/gobotcore"
+)
+
+const (
+	defaultMoveTime = 5 * time.Second
+	defaultHintTime = time.Second
+	defaultSavePath = "gobot-game.txt"
+)
+
+// ANSI escape codes
+const (
+	clearScreen     = "\x1b[H\x1b[2J"
+	enterAltScreen  = "\x1b[?1049h\x1b[?25l"
+	leaveAltScreen  = "\x1b[?25h\x1b[?1049l"
+	reset           = "\x1b[0m"
+	darkSquare      = "\x1b[48;5;94m"
+	lightSquare     = "\x1b[48;5;180m"
+	cursorSquare    = "\x1b[48;5;33m"
+	selectedSquare  = "\x1b[48;5;220m"
+	targetSquare    = "\x1b[48;5;70m"
+	lastMoveSquare  = "\x1b[48;5;139m"
+	gobotPiece      = "\x1b[1;38;5;52m"
+	humanPiece      = "\x1b[1;38;5;231m"
+	messageColor    = "\x1b[1;33m"
+	subtleTextColor = "\x1b[2m"
+)
+
+type Options struct {
+	GobotFirst bool
+	MoveTime   time.Duration // How long Gobot thinks about its moves
+	HintTime   time.Duration // How long Gobot thinks about a hint
+	SavePath   string
+}
+
+type key int
+
+const (
+	keyRune key = iota
+	keyUp
+	keyDown
+	keyLeft
+	keyRight
+	keyEnter
+)
+
+type UI struct {
+	game    *gobotcore.Game
+	options Options
+	in      *bufio.Reader
+	out     io.Writer
+
+	cursorCol, cursorRow int8
+	selected             bool
+	selectedCol          int8
+	selectedRow          int8
+	flipped              bool
+	message              string
+}
+
+func NewUI(game *gobotcore.Game, options Options, in io.Reader, out io.Writer) *UI {
+	if options.MoveTime <= 0 {
+		options.MoveTime = defaultMoveTime
+	}
+	if options.HintTime <= 0 {
+		options.HintTime = defaultHintTime
+	}
+	if options.SavePath == "" {
+		options.SavePath = defaultSavePath
+	}
+	return &UI{game: game, options: options, in: bufio.NewReader(in), out: out, cursorCol: 2, cursorRow: 1}
+}
+
+// Run plays a new game in the terminal, which is put into raw mode until the game is done
+func Run(options Options) error {
+	restore, err := makeRaw(os.Stdin)
+	if err != nil {
+		return errors.New("could not put the terminal into raw mode: " + err.Error())
+	}
+	defer restore()
+
+	first := gobotcore.Player(gobotcore.HUMAN)
+	if options.GobotFirst {
+		first = gobotcore.GOBOT
+	}
+	fmt.Print(enterAltScreen)
+	defer fmt.Print(leaveAltScreen)
+	return NewUI(gobotcore.NewGame(first), options, os.Stdin, os.Stdout).Run()
+}
+
+// Run reads keys until the player quits or the input ends
+func (ui *UI) Run() error {
+	ui.message = "Move the cursor with the arrow keys and pick a piece with enter."
+	if ui.game.Turn() == gobotcore.GOBOT {
+		ui.gobotMove()
+	}
+	for {
+		ui.draw()
+		pressed, char, err := ui.readKey()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if !ui.handleKey(pressed, char) {
+			return nil
+		}
+	}
+}
+
+// Returns false when the player wants to quit
+func (ui *UI) handleKey(pressed key, char rune) bool {
+	up, down, left, right := int8(1), int8(-1), int8(-1), int8(1)
+	if ui.flipped {
+		up, down, left, right = -up, -down, -left, -right
+	}
+
+	switch {
+	case pressed == keyUp || char == 'k':
+		ui.moveCursor(0, up)
+	case pressed == keyDown || char == 'j':
+		ui.moveCursor(0, down)
+	case pressed == keyLeft || char == 'h':
+		ui.moveCursor(left, 0)
+	case pressed == keyRight || char == 'l':
+		ui.moveCursor(right, 0)
+	case pressed == keyEnter || char == ' ':
+		ui.pick()
+	case char == 'u':
+		ui.undo()
+	case char == '?':
+		ui.hint()
+	case char == 'f':
+		ui.flipped = !ui.flipped
+	case char == 's':
+		ui.save()
+	case char == 'q' || char == 3: // 3 is ctrl-c in raw mode
+		return false
+	}
+	return true
+}
+
+func (ui *UI) moveCursor(cols int8, rows int8) {
+	boardCols, boardRows := gobotcore.BoardSize()
+	col, row := ui.cursorCol+cols, ui.cursorRow+rows
+	if col >= 0 && col < boardCols && row >= 0 && row < boardRows {
+		ui.cursorCol, ui.cursorRow = col, row
+	}
+}
+
+// Selects the piece under the cursor, or moves the selected piece to the cursor
+func (ui *UI) pick() {
+	if ui.game.IsOver() {
+		ui.message = "The game is over. Press q to quit or u to take back."
+		return
+	}
+	human := gobotcore.Player(gobotcore.HUMAN)
+	board := ui.game.Board()
+	cursor := gobotcore.NewLocation(ui.cursorCol, ui.cursorRow)
+	piece := board.PieceAt(&cursor)
+
+	if piece.IsOwnedBy(&human) {
+		if ui.selected && ui.selectedCol == ui.cursorCol && ui.selectedRow == ui.cursorRow {
+			ui.selected = false
+			ui.message = ""
+			return
+		}
+		ui.selected, ui.selectedCol, ui.selectedRow = true, ui.cursorCol, ui.cursorRow
+		ui.message = "Selected your " + piece.LongName() + " on " + cursor.ToString() + ". Now pick where it should go."
+		return
+	}
+	if !ui.selected {
+		if piece.IsEmpty() {
+			ui.message = "There is no piece on " + cursor.ToString() + "."
+		} else {
+			ui.message = "That is one of Gobot's pieces. Pick one of yours."
+		}
+		return
+	}
+
+	move := gobotcore.NewMove(gobotcore.NewLocation(ui.selectedCol, ui.selectedRow), cursor)
+	if reason := board.WhyIllegal(&move, human); reason != "" {
+		ui.message = move.ToString() + " is not allowed. " + reason
+		return
+	}
+	ui.selected = false
+	ui.game.MakeMove(move)
+	ui.message = describeMove(ui.lastPlayed(), "You")
+	if !ui.game.IsOver() {
+		ui.gobotMove()
+	}
+}
+
+func (ui *UI) gobotMove() {
+	ui.message = "Gobot is thinking..."
+	ui.draw()
+
+	gobot := gobotcore.Player(gobotcore.GOBOT)
+	board := ui.game.Board()
+	best := board.Search(&gobot, gobotcore.SearchLimits{MoveTime: ui.options.MoveTime}, nil, nil)
+	if err := ui.game.MakeMove(*best.Move()); err != nil {
+		ui.message = "Gobot could not find a move."
+		return
+	}
+	ui.message = describeMove(ui.lastPlayed(), "Gobot")
+}
+
+// Takes back moves until it is the human's turn again, with at least one human move taken back
+func (ui *UI) undo() {
+	ui.selected = false
+	humanMoved := false
+	for _, played := range ui.game.History() {
+		humanMoved = humanMoved || played.Player == gobotcore.HUMAN
+	}
+	// Gobot's first move alone isn't taken back, Gobot would only play it again
+	if !humanMoved {
+		ui.message = "There is nothing to take back."
+		return
+	}
+	ui.game.Undo()
+	for ui.game.Turn() == gobotcore.GOBOT {
+		ui.game.Undo()
+	}
+	ui.message = "Took back your last move."
+}
+
+func (ui *UI) hint() {
+	if ui.game.IsOver() || ui.game.Turn() != gobotcore.HUMAN {
+		return
+	}
+	ui.message = "Thinking about a hint..."
+	ui.draw()
+
+	human := gobotcore.Player(gobotcore.HUMAN)
+	board := ui.game.Board()
+	best := board.Search(&human, gobotcore.SearchLimits{MoveTime: ui.options.HintTime}, nil, nil)
+	move := best.Move()
+	ui.selected, ui.selectedCol, ui.selectedRow = true, move.From().Col(), move.From().Row()
+	ui.cursorCol, ui.cursorRow = move.To().Col(), move.To().Row()
+	ui.message = fmt.Sprintf("Hint: %s (expected line %s). Press enter to play it.", move.ToString(), best.PV().ToString())
+}
+
+func (ui *UI) save() {
+	if err := ui.game.SaveRecord(ui.options.SavePath); err != nil {
+		ui.message = "Could not save: " + err.Error()
+		return
+	}
+	ui.message = "Saved the game to " + ui.options.SavePath + "."
+}
+
+func (ui *UI) lastPlayed() gobotcore.PlayedMove {
+	history := ui.game.History()
+	return history[len(history)-1]
+}
+
+func describeMove(played gobotcore.PlayedMove, who string) string {
+	message := who + " played " + played.Move.ToString()
+	if !played.TakenPiece.IsEmpty() {
+		message += " and took a " + played.TakenPiece.LongName()
+	}
+	if morphed := played.Piece.Morph(); morphed != played.Piece {
+		message += ". The " + played.Piece.LongName() + " became a " + morphed.LongName()
+	}
+	return message + "."
+}
+
+// ================== Drawing ==================
+
+func (ui *UI) draw() {
+	var screen strings.Builder
+	screen.WriteString(clearScreen)
+	board := ui.game.Board()
+	boardCols, boardRows := gobotcore.BoardSize()
+	side := ui.sidePanel()
+	for len(side) < int(boardRows) {
+		side = append(side, "")
+	}
+
+	targets := map[gobotcore.Location]bool{}
+	if ui.selected {
+		from := gobotcore.NewLocation(ui.selectedCol, ui.selectedRow)
+		for _, move := range ui.game.LegalMoves() {
+			if move.From().Equals(&from) {
+				targets[*move.To()] = true
+			}
+		}
+	}
+	var last *gobotcore.Move
+	if history := ui.game.History(); len(history) > 0 {
+		last = &history[len(history)-1].Move
+	}
+
+	line := 0
+	for i := int8(0); i < boardRows; i++ {
+		row := boardRows - 1 - i
+		if ui.flipped {
+			row = i
+		}
+		screen.WriteString(fmt.Sprintf(" %d ", row+1))
+		for j := int8(0); j < boardCols; j++ {
+			col := j
+			if ui.flipped {
+				col = boardCols - 1 - j
+			}
+			location := gobotcore.NewLocation(col, row)
+			piece := board.PieceAt(&location)
+			screen.WriteString(ui.squareColor(location, targets, last))
+			name := piece.GetName()
+			if piece.IsEmpty() {
+				name = " "
+			}
+			screen.WriteString(pieceColor(piece) + " " + name + " " + reset)
+		}
+		screen.WriteString("   " + side[line] + "\r\n")
+		line++
+	}
+
+	screen.WriteString("   ")
+	for j := int8(0); j < boardCols; j++ {
+		col := j
+		if ui.flipped {
+			col = boardCols - 1 - j
+		}
+		screen.WriteString(" " + string(rune('A'+col)) + " ")
+	}
+	screen.WriteString("\r\n\r\n")
+	for _, rest := range side[line:] {
+		screen.WriteString(rest + "\r\n")
+	}
+	screen.WriteString("\r\n" + messageColor + ui.message + reset + "\r\n")
+	fmt.Fprint(ui.out, screen.String())
+}
+
+func (ui *UI) squareColor(location gobotcore.Location, targets map[gobotcore.Location]bool, last *gobotcore.Move) string {
+	switch {
+	case location.Col() == ui.cursorCol && location.Row() == ui.cursorRow:
+		return cursorSquare
+	case ui.selected && location.Col() == ui.selectedCol && location.Row() == ui.selectedRow:
+		return selectedSquare
+	case targets[location]:
+		return targetSquare
+	case last != nil && (location.Equals(last.From()) || location.Equals(last.To())):
+		return lastMoveSquare
+	case (location.Col()+location.Row())%2 == 0:
+		return darkSquare
+	}
+	return lightSquare
+}
+
+func pieceColor(piece gobotcore.Piece) string {
+	gobot := gobotcore.Player(gobotcore.GOBOT)
+	if piece.IsOwnedBy(&gobot) {
+		return gobotPiece
+	}
+	return humanPiece
+}
+
+func (ui *UI) sidePanel() []string {
+	lines := []string{"Gobot (uppercase) vs You (lowercase)"}
+	if winner, over := ui.game.Winner(); over {
+		if winner == gobotcore.HUMAN {
+			lines = append(lines, "You won!")
+		} else {
+			lines = append(lines, "Gobot won.")
+		}
+	} else if ui.game.Turn() == gobotcore.HUMAN {
+		lines = append(lines, "Your move")
+	} else {
+		lines = append(lines, "Gobot's move")
+	}
+
+	var byGobot, byHuman []string
+	history := ui.game.History()
+	for _, played := range history {
+		if played.TakenPiece.IsEmpty() {
+			continue
+		}
+		if played.Player == gobotcore.GOBOT {
+			byGobot = append(byGobot, played.TakenPiece.GetName())
+		} else {
+			byHuman = append(byHuman, played.TakenPiece.GetName())
+		}
+	}
+	lastMove := "-"
+	if len(history) > 0 {
+		lastMove = history[len(history)-1].Move.ToString()
+	}
+	lines = append(lines,
+		"Last move: "+lastMove,
+		"You took:  "+strings.Join(byHuman, " "),
+		"Gobot took: "+strings.Join(byGobot, " "),
+		"",
+		subtleTextColor+"arrows/hjkl move  enter pick"+reset,
+		subtleTextColor+"u undo  ? hint  f flip  s save  q quit"+reset,
+	)
+	return lines
+}
+
+// ================== Input ==================
+
+func (ui *UI) readKey() (key, rune, error) {
+	char, _, err := ui.in.ReadRune()
+	if err != nil {
+		return keyRune, 0, err
+	}
+	switch char {
+	case '\r', '\n':
+		return keyEnter, 0, nil
+	case 0x1b:
+		// Arrow keys are sent as ESC [ A through ESC [ D
+		if next, _, err := ui.in.ReadRune(); err != nil || next != '[' {
+			return keyRune, 0x1b, nil
+		}
+		arrow, _, err := ui.in.ReadRune()
+		if err != nil {
+			return keyRune, 0, err
+		}
+		switch arrow {
+		case 'A':
+			return keyUp, 0, nil
+		case 'B':
+			return keyDown, 0, nil
+		case 'C':
+			return keyRight, 0, nil
+		case 'D':
+			return keyLeft, 0, nil
+		}
+		return keyRune, 0, nil
+	}
+	return keyRune, char, nil
+}
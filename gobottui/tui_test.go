@@ -0,0 +1,82 @@
+package gobottui
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ktodaz/gobot/gobotcore"
+)
+
+func newTestUI(keys string) (*UI, *strings.Builder) {
+	var out strings.Builder
+	options := Options{MoveTime: 20 * time.Millisecond, HintTime: 20 * time.Millisecond}
+	return NewUI(gobotcore.NewGame(gobotcore.HUMAN), options, strings.NewReader(keys), &out), &out
+}
+
+func TestUI_MoveAndUndo(t *testing.T) {
+	// The cursor starts on C2. Go up to the pawn on C3 and push it to C4
+	ui, _ := newTestUI("k\rk\r")
+	if err := ui.Run(); err != nil {
+		t.Fatal(err)
+	}
+	history := ui.game.History()
+	if len(history) != 2 || history[0].Move.ToString() != "C3C4" {
+		t.Fatal("Should have played C3C4 and Gobot's reply")
+	}
+
+	ui.handleKey(keyRune, 'u')
+	if len(ui.game.History()) != 0 || ui.game.Turn() != gobotcore.HUMAN {
+		t.Error("Undo should take back both moves")
+	}
+}
+
+func TestUI_UndoGobotFirstMove(t *testing.T) {
+	game := gobotcore.NewGame(gobotcore.GOBOT)
+	game.MakeMove(gobotcore.NewMoveFromString("C6C5"))
+	ui := NewUI(game, Options{MoveTime: 20 * time.Millisecond}, strings.NewReader(""), &strings.Builder{})
+	ui.handleKey(keyRune, 'u')
+	if len(ui.game.History()) != 1 || ui.message != "There is nothing to take back." {
+		t.Error("Gobot's first move alone shouldn't be taken back, got: " + ui.message)
+	}
+}
+
+func TestUI_IllegalMove(t *testing.T) {
+	// Select the pawn on C3 and try to move it two squares
+	ui, out := newTestUI("k\rkk\r")
+	ui.Run()
+	if len(ui.game.History()) != 0 {
+		t.Error("Illegal move should not be played")
+	}
+	if !strings.Contains(ui.message, "C3C5 is not allowed. A pawn only moves one square forward.") {
+		t.Error("Should explain why the move is illegal, got: " + ui.message)
+	}
+	if !strings.Contains(out.String(), "Your move") {
+		t.Error("Should draw the board")
+	}
+}
+
+func TestUI_ArrowKeysAndFlip(t *testing.T) {
+	ui, _ := newTestUI("\x1b[A\x1b[Cf\x1b[A")
+	ui.Run()
+	if ui.cursorCol != 3 || ui.cursorRow != 1 {
+		t.Error("Up on a flipped board should move the cursor down")
+	}
+}
+
+func TestUI_HintAndSave(t *testing.T) {
+	ui, _ := newTestUI("?\rs")
+	ui.options.SavePath = filepath.Join(t.TempDir(), "game.txt")
+	ui.Run()
+	if len(ui.game.History()) != 2 {
+		t.Error("Enter after a hint should play the hinted move")
+	}
+	saved, err := gobotcore.LoadGameRecord(ui.options.SavePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if saved.Position() != ui.game.Position() {
+		t.Error("Saved game should match the game")
+	}
+}
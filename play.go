@@ -0,0 +1,119 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"github.com/ktodaz/gobot/gobotcore"
+	"os"
+	"time"
+)
+
+// The game record is saved here after every move, so a game can be resumed with -resume. Empty turns autosave off
+var autosavePath string
+
+// gobot play [-variant standard|mini|large] [-style ascii|unicode|color] [-coords edges|none|around] [-flip] [-autosave file] [-resume file]
+// [-first gobot|human] [-gobot engine|console|mcts|random|greedy|replay|external] [-human console|engine|...]
+// [-level beginner|easy|medium|hard|max] [-personality balanced|aggressive|defensive] [-evaluator classic|weights] [-movetime 5s] [-ponder]
+// [-gobot-cmd "engine args"] [-gobot-record file]
+// [-human-level ...] [-human-personality ...] [-human-evaluator ...] [-human-movetime 5s] [-human-cmd "engine args"] [-human-record file]
+// An external player is another engine that speaks the text protocol of package gobotproto.
+// A replay player plays its side's moves from a game record. See package gobotagent for the others.
+// Errors are returned rather than exited on, so the deferred closePlayers still shuts down external engines
+func play(args []string) error {
+	flags := flag.NewFlagSet("play", flag.ExitOnError)
+	variantName := variantFlag(flags)
+	style := flags.String("style", "ascii", "how to draw the board: ascii, unicode or color")
+	coords := flags.String("coords", "edges", "where to draw coordinates: edges, none or around")
+	flip := flags.Bool("flip", false, "draw the board from Gobot's side")
+	flags.StringVar(&autosavePath, "autosave", "gobot-game.txt", "file to save the game to after every move, empty for none")
+	resume := flags.String("resume", "", "continue the game saved in this file")
+	first := flags.String("first", "", "who moves first, gobot or human. Asks if not given")
+	ponder := flags.Bool("ponder", false, "let engine players keep thinking on their opponent's time")
+	gobotConfig := playerConfig{}
+	flags.StringVar(&gobotConfig.kind, "gobot", "engine", "who plays Gobot's side: engine, console, mcts, random, greedy, replay or external")
+	flags.StringVar(&gobotConfig.command, "gobot-cmd", "", "command line of the external engine playing Gobot's side")
+	flags.StringVar(&gobotConfig.record, "gobot-record", "", "game record a replay player on Gobot's side plays from")
+	flags.StringVar(&gobotConfig.level, "level", "", "Gobot's difficulty: beginner, easy, medium, hard or max")
+	flags.StringVar(&gobotConfig.personality, "personality", "", "Gobot's style: balanced, aggressive or defensive")
+	flags.StringVar(&gobotConfig.evaluator, "evaluator", "classic", "how Gobot scores positions: classic or a network weights file from gobot train")
+	flags.DurationVar(&gobotConfig.moveTime, "movetime", 5*time.Second, "time per move for Gobot's side")
+	humanConfig := playerConfig{}
+	flags.StringVar(&humanConfig.kind, "human", "console", "who plays Human's side: console, engine, mcts, random, greedy, replay or external")
+	flags.StringVar(&humanConfig.command, "human-cmd", "", "command line of the external engine playing Human's side")
+	flags.StringVar(&humanConfig.record, "human-record", "", "game record a replay player on Human's side plays from")
+	flags.StringVar(&humanConfig.level, "human-level", "", "difficulty of an engine playing Human's side")
+	flags.StringVar(&humanConfig.personality, "human-personality", "", "style of an engine playing Human's side")
+	flags.StringVar(&humanConfig.evaluator, "human-evaluator", "classic", "how an engine playing Human's side scores positions")
+	flags.DurationVar(&humanConfig.moveTime, "human-movetime", 5*time.Second, "time per move for Human's side")
+	flags.Parse(args)
+	gobotConfig.ponder, humanConfig.ponder = *ponder, *ponder
+	useVariant(*variantName)
+
+	var err error
+	if renderer.Style, err = gobotcore.ParseRenderStyle(*style); err != nil {
+		return err
+	}
+	if renderer.Coordinates, err = gobotcore.ParseCoordinateStyle(*coords); err != nil {
+		return err
+	}
+	renderer.Flipped = *flip
+	gobotcore.SetRenderer(renderer)
+
+	defer closePlayers()
+	for side, config := range map[gobotcore.Player]playerConfig{gobotcore.GOBOT: gobotConfig, gobotcore.HUMAN: humanConfig} {
+		if players[side], err = newPlayer(config); err != nil {
+			return errors.New(side.Name() + ": " + err.Error())
+		}
+	}
+
+	gobotcore.SetDebug(false)
+	if *resume != "" {
+		autosaveSet := false
+		flags.Visit(func(f *flag.Flag) {
+			autosaveSet = autosaveSet || f.Name == "autosave"
+		})
+		if !autosaveSet {
+			// Keep saving to the file being resumed
+			autosavePath = *resume
+		}
+		return resumeGame(*resume)
+	}
+	switch *first {
+	case "gobot":
+		isGobotGoingFirst = true
+	case "human":
+		isGobotGoingFirst = false
+	default:
+		isGobotGoingFirst = IsGobotGoingFirst()
+	}
+	GameLoop(isGobotGoingFirst)
+	return nil
+}
+
+func resumeGame(path string) error {
+	saved, err := gobotcore.LoadGameRecord(path)
+	if err != nil {
+		return err
+	}
+	setGame(saved)
+	fmt.Printf("\nResumed game from %s after %d moves, %s to move:", path, len(saved.History()), saved.Turn().Name())
+	renderer.Print(&board)
+	playGame()
+	return nil
+}
+
+// Called by main once play has returned, after its cleanup
+func playFail(err error) {
+	fmt.Fprintln(os.Stderr, "gobot play:", err)
+	os.Exit(1)
+}
+
+func autosave() {
+	if autosavePath == "" {
+		return
+	}
+	if err := game.SaveRecord(autosavePath); err != nil {
+		fmt.Println("Could not autosave the game: " + err.Error())
+	}
+}
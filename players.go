@@ -0,0 +1,137 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"github.com/ktodaz/gobot/gobotagent"
+	"github.com/ktodaz/gobot/gobotcore"
+	"strings"
+	"time"
+)
+
+// Who plays each side. A side without an agent is played by a person at the console
+var players = map[gobotcore.Player]gobotagent.Agent{
+	gobotcore.GOBOT: gobotagent.NewMinimaxAgent(),
+}
+
+// Settings for one side from the command line
+type playerConfig struct {
+	kind        string // console, engine, mcts, random, greedy, replay or external
+	command     string // Command line of an external engine
+	record      string // Game record a replay player plays the moves of
+	level       string
+	personality string
+	evaluator   string // classic or a network weights file
+	moveTime    time.Duration
+	ponder      bool // Engine players think on the opponent's time
+}
+
+func newPlayer(config playerConfig) (gobotagent.Agent, error) {
+	switch config.kind {
+	case "console":
+		return nil, nil
+	case "engine":
+		engine := gobotagent.NewMinimaxAgent()
+		engine.Limits.MoveTime = config.moveTime
+		engine.Ponder = config.ponder
+		if config.level != "" {
+			difficulty, err := gobotcore.ParseDifficulty(config.level)
+			if err != nil {
+				return nil, err
+			}
+			difficulty.Apply(&engine.Limits, &engine.Options)
+			engine.Limits.StartDepth = 0
+		}
+		if config.personality != "" {
+			personality, err := gobotcore.ParsePersonality(config.personality)
+			if err != nil {
+				return nil, err
+			}
+			engine.Options.Personality = personality
+		}
+		network, err := gobotcore.ParseEvaluator(config.evaluator)
+		if err != nil {
+			return nil, err
+		}
+		engine.Options.Network = network
+		return engine, nil
+	case "mcts":
+		mcts := gobotagent.NewMCTSAgent(time.Now().UnixNano())
+		mcts.MoveTime = config.moveTime
+		return mcts, nil
+	case "random":
+		return gobotagent.NewRandomAgent(time.Now().UnixNano()), nil
+	case "greedy":
+		return gobotagent.NewGreedyAgent(time.Now().UnixNano()), nil
+	case "replay":
+		replay, err := gobotagent.LoadScriptedAgent(config.record)
+		if err != nil {
+			return nil, err
+		}
+		return replay, nil
+	case "external":
+		args := strings.Fields(config.command)
+		if len(args) == 0 {
+			return nil, errors.New("an external player needs the engine's command line")
+		}
+		external, err := gobotagent.StartExternalAgent(config.moveTime, args[0], args[1:]...)
+		if err != nil {
+			return nil, err
+		}
+		return external, nil
+	}
+	return nil, errors.New("player must be console, engine, mcts, random, greedy, replay or external")
+}
+
+func closePlayers() {
+	for _, agent := range players {
+		switch agent := agent.(type) {
+		case *gobotagent.ExternalAgent:
+			agent.Close()
+		case *gobotagent.MinimaxAgent:
+			agent.StopPondering()
+		}
+	}
+}
+
+// Lets the player of the side to move make its move. Returns false if it couldn't
+func playerMoveFriendly(agent gobotagent.Agent) bool {
+	side := game.Turn()
+	move, err := agent.ChooseMove(game)
+	if err == nil {
+		err = game.MakeMove(move)
+	}
+	if err != nil {
+		fmt.Printf("\n%s could not move: %s\n", side.Name(), err)
+		return false
+	}
+
+	if engine, ok := agent.(*gobotagent.MinimaxAgent); ok {
+		if engine.LastPonderHit {
+			fmt.Print("\nPonder hit")
+		}
+		fmt.Printf("\nReturned score: %s", gobotcore.FormatScore(engine.LastScore))
+	}
+	history := game.History()
+	printMoveMessage(history[len(history)-1])
+	board = game.Board()
+	renderer.LastMove = &move
+	renderer.Print(&board)
+	return true
+}
+
+func printMoveMessage(played gobotcore.PlayedMove) {
+	fmt.Printf("\n%s made move %s", played.Player.Name(), played.Move.ToString())
+	if played.Player == gobotcore.GOBOT {
+		fmt.Printf(" (%s)", played.Move.ToStringFlipped())
+	}
+	if !played.TakenPiece.IsEmpty() {
+		fmt.Printf(" and captured %s piece %s", played.Player.Opponent().Name(), played.TakenPiece.GetName())
+	}
+	fmt.Println()
+}
+
+// True if both sides are played at the console
+func isHumanVsHuman() bool {
+	return players[gobotcore.GOBOT] == nil && players[gobotcore.HUMAN] == nil
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"github.com/ktodaz/gobot/gobotcore"
+	"github.com/ktodaz/gobot/gobotimage"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// gobot render [-variant standard|mini|large] [-position pos | -game record] -out file.png|file.svg|file.gif [-move C3C4] [-pv "C3C4 D7D6"]
+// [-highlight "C3,D4"] [-size 60] [-coords] [-flip] [-delay 100]
+// A gif needs -game and shows every position of the game
+func render(args []string) {
+	flags := flag.NewFlagSet("render", flag.ExitOnError)
+	variantName := variantFlag(flags)
+	position := flags.String("position", "", "position to draw, the start position of the variant if empty")
+	record := flags.String("game", "", "game record to draw, its final position or every position for a gif")
+	out := flags.String("out", "", "file to write, the extension picks the format: .png, .svg or .gif")
+	move := flags.String("move", "", "move to draw an arrow for")
+	pv := flags.String("pv", "", "moves separated by spaces to draw as fading arrows")
+	highlight := flags.String("highlight", "", "squares to highlight separated by commas")
+	size := flags.Int("size", 60, "width of a square in pixels")
+	coords := flags.Bool("coords", false, "draw coordinates around the board")
+	flip := flags.Bool("flip", false, "draw the board from Gobot's side")
+	delay := flags.Int("delay", 100, "time between gif frames in hundredths of a second")
+	flags.Parse(args)
+	useVariant(*variantName)
+	if *position == "" {
+		*position = gobotcore.CurrentVariant().Start
+	}
+
+	if *out == "" {
+		renderFail("-out is required")
+	}
+	options := gobotimage.Options{SquareSize: *size, Coordinates: *coords, Flipped: *flip}
+	for _, str := range strings.Fields(*move + " " + *pv) {
+		arrow, err := gobotcore.ParseMove(str)
+		if err != nil {
+			renderFail(err.Error())
+		}
+		options.Arrows = append(options.Arrows, arrow)
+	}
+	for _, str := range strings.Split(*highlight, ",") {
+		if str = strings.TrimSpace(str); str == "" {
+			continue
+		}
+		location, err := gobotcore.ParseLocation(str)
+		if err != nil {
+			renderFail(err.Error())
+		}
+		options.Highlights = append(options.Highlights, location)
+	}
+
+	var game *gobotcore.Game
+	var err error
+	if *record != "" {
+		game, err = gobotcore.LoadGameRecord(*record)
+	} else {
+		game, err = gobotcore.NewGameFromPosition(*position)
+	}
+	if err != nil {
+		renderFail(err.Error())
+	}
+
+	file, err := os.Create(*out)
+	if err != nil {
+		renderFail(err.Error())
+	}
+	board := game.Board()
+	switch strings.ToLower(filepath.Ext(*out)) {
+	case ".png":
+		err = gobotimage.WritePNG(file, &board, options)
+	case ".svg":
+		err = gobotimage.WriteSVG(file, &board, options)
+	case ".gif":
+		err = gobotimage.WriteGIF(file, game, options, *delay)
+	default:
+		err = fmt.Errorf("don't know how to write %s, use .png, .svg or .gif", *out)
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(*out)
+		renderFail(err.Error())
+	}
+}
+
+func renderFail(message string) {
+	fmt.Fprintln(os.Stderr, "gobot render:", message)
+	os.Exit(2)
+}
@@ -0,0 +1,26 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"github.com/ktodaz/gobot/gobotcore"
+	"github.com/ktodaz/gobot/gobotserver"
+	"net/http"
+	"os"
+)
+
+// gobot serve [-addr localhost:8080] [-variant standard|mini|large]
+func serve(args []string) {
+	flags := flag.NewFlagSet("serve", flag.ExitOnError)
+	addr := flags.String("addr", "localhost:8080", "address to listen on")
+	variantName := variantFlag(flags)
+	flags.Parse(args)
+	useVariant(*variantName)
+
+	gobotcore.SetDebug(false)
+	fmt.Println("Open http://" + *addr + "/ to play, the API is under /api/")
+	if err := http.ListenAndServe(*addr, gobotserver.NewServer()); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
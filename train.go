@@ -0,0 +1,119 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"github.com/ktodaz/gobot/gobotcore"
+	"os"
+)
+
+// gobot train -out weights.gbnn [-variant standard|mini|large] [-init weights.gbnn] [-games 20] [-depth 3] [-randomness 3] [-max-moves 150]
+// [-epochs 20] [-rate 0.2] [-result-weight 0.5] [-hidden1 32] [-hidden2 16] [-seed 1]
+// Plays games against itself, then trains a network on every position of them, labeled with the search's score and who won.
+// With -init the games are played with that network and training goes on from it, so running train again with
+// -init set to the last -out keeps improving it. The weights can be used with -evaluator, see gobotcore.Network
+func train(args []string) {
+	flags := flag.NewFlagSet("train", flag.ExitOnError)
+	out := flags.String("out", "", "file to write the trained weights to")
+	variantName := variantFlag(flags)
+	initPath := flags.String("init", "", "weights to play the games with and start training from, instead of a new network")
+	games := flags.Int("games", 20, "how many self-play games to learn from")
+	depth := flags.Int("depth", 3, "depth of the search for every move of the games")
+	randomness := flags.Float64("randomness", 3, "play any move within this much of the best, so the games differ")
+	maxMoves := flags.Int("max-moves", 150, "moves after which an unfinished game counts as a draw")
+	epochs := flags.Int("epochs", 20, "how many times to go over the positions")
+	rate := flags.Float64("rate", 0.2, "learning rate")
+	resultWeight := flags.Float64("result-weight", 0.5, "how much the game result counts against the search score, from 0 to 1")
+	hidden1 := flags.Int("hidden1", 32, "size of the first hidden layer of a new network")
+	hidden2 := flags.Int("hidden2", 16, "size of the second hidden layer of a new network")
+	seed := flags.Int64("seed", 1, "seed for the games, the new network and the order of training")
+	flags.Parse(args)
+	useVariant(*variantName)
+
+	if *out == "" {
+		trainFail("-out has to name the weights file to write")
+	}
+	if *depth < 1 || *games < 1 || *epochs < 1 || *hidden1 < 1 || *hidden2 < 1 {
+		trainFail("-games, -depth, -epochs, -hidden1 and -hidden2 have to be at least 1")
+	}
+	if *resultWeight < 0 || *resultWeight > 1 {
+		trainFail("-result-weight has to be from 0 to 1")
+	}
+
+	gobotcore.SetDebug(false)
+	var network *gobotcore.Network
+	options := gobotcore.SearchOptions{Randomness: float32(*randomness), Deterministic: true}
+	if *initPath != "" {
+		var err error
+		if network, err = gobotcore.LoadNetwork(*initPath); err != nil {
+			trainFail(err.Error())
+		}
+		options.Network = network
+	} else {
+		network = gobotcore.NewNetwork(*hidden1, *hidden2, *seed)
+	}
+
+	var positions []gobotcore.TrainingPosition
+	for i := 0; i < *games; i++ {
+		options.Seed = *seed + int64(i)
+		first := gobotcore.Player(gobotcore.HUMAN)
+		if i%2 == 1 {
+			first = gobotcore.GOBOT
+		}
+		gamePositions, result := selfPlay(first, gobotcore.SearchLimits{Depth: int8(*depth)}, options, *maxMoves)
+		positions = append(positions, gamePositions...)
+		fmt.Printf("game %d: %s after %d moves\n", i+1, result, len(gamePositions))
+	}
+
+	trainingOptions := gobotcore.TrainingOptions{
+		Epochs:       *epochs,
+		LearningRate: float32(*rate),
+		ResultWeight: float32(*resultWeight),
+		Seed:         *seed,
+	}
+	fmt.Printf("training on %d positions\n", len(positions))
+	network.Train(positions, trainingOptions, func(epoch int, loss float32) {
+		fmt.Printf("epoch %d: loss %.5f\n", epoch, loss)
+	})
+	if err := network.Save(*out); err != nil {
+		trainFail(err.Error())
+	}
+	fmt.Println("Wrote the weights to " + *out)
+}
+
+// Plays a game from the start position and returns every position of it with the search's score, labeled with the result
+func selfPlay(first gobotcore.Player, limits gobotcore.SearchLimits, options gobotcore.SearchOptions, maxMoves int) ([]gobotcore.TrainingPosition, string) {
+	game := gobotcore.NewGame(first)
+	var positions []gobotcore.TrainingPosition
+	for len(positions) < maxMoves && !game.IsOver() {
+		board := game.Board()
+		player := game.Turn()
+		best := board.SearchWithOptions(&player, limits, options, nil, nil)
+		score := *best.Score()
+		if player == gobotcore.HUMAN {
+			score = -score
+		}
+		positions = append(positions, gobotcore.TrainingPosition{Board: board, Score: score})
+		if err := game.MakeMove(*best.Move()); err != nil {
+			trainFail(err.Error())
+		}
+		options.Seed++
+	}
+
+	result, description := float32(0.5), "unfinished"
+	if winner, over := game.Winner(); over {
+		result, description = 0, "Human won"
+		if winner == gobotcore.GOBOT {
+			result, description = 1, "Gobot won"
+		}
+	}
+	for i := range positions {
+		positions[i].Result = result
+	}
+	return positions, description
+}
+
+func trainFail(message string) {
+	fmt.Fprintln(os.Stderr, "gobot train:", message)
+	os.Exit(2)
+}
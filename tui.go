@@ -0,0 +1,27 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"github.com/ktodaz/gobot/gobotcore"
+	"github.com/ktodaz/gobot/gobottui"
+	"os"
+)
+
+// gobot tui [-variant standard|mini|large] [-first] [-movetime 5s] [-save gobot-game.txt]
+func tui(args []string) {
+	flags := flag.NewFlagSet("tui", flag.ExitOnError)
+	options := gobottui.Options{}
+	flags.BoolVar(&options.GobotFirst, "first", false, "let Gobot make the first move")
+	flags.DurationVar(&options.MoveTime, "movetime", 0, "how long Gobot thinks about each move")
+	flags.StringVar(&options.SavePath, "save", "", "file the s key saves the game to")
+	variantName := variantFlag(flags)
+	flags.Parse(args)
+	useVariant(*variantName)
+
+	gobotcore.SetDebug(false)
+	if err := gobottui.Run(options); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
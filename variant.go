@@ -0,0 +1,26 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"github.com/ktodaz/gobot/gobotcore"
+	"os"
+)
+
+// Adds -variant to a command's flags. Pass the value to useVariant once the flags are parsed
+func variantFlag(flags *flag.FlagSet) *string {
+	return flags.String("variant", "standard", "board to play on: standard (6x8), mini (5x6) or large (8x8)")
+}
+
+// Switches to the variant for the rest of the program, before any board is made
+func useVariant(name string) {
+	variant, err := gobotcore.ParseVariant(name)
+	if err == nil {
+		err = gobotcore.SetVariant(variant)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	board = gobotcore.NewDefaultBoard()
+}